@@ -0,0 +1,164 @@
+package tempmail
+
+import (
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/idna"
+)
+
+const (
+	maxAddressLength = 254
+	maxLocalLength   = 64
+	maxDomainLength  = 253
+	maxLabelLength   = 63
+)
+
+// Address is a parsed email address.
+type Address struct {
+	// Local is the part before "@". Its case is preserved.
+	Local string
+	// Domain is the lowercase ASCII domain. Internationalized domains are converted to punycode.
+	Domain string
+}
+
+// String returns the address in the "local@domain" form.
+func (a Address) String() string {
+	return a.Local + "@" + a.Domain
+}
+
+// CheckDomain returns a *ValidationError if the domain of the address is not one of the given domains,
+// for example the result of Client.ListDomains.
+func (a Address) CheckDomain(domains []ListDomainsDomainResponse) error {
+	for _, d := range domains {
+		if strings.EqualFold(d.Name, a.Domain) {
+			return nil
+		}
+	}
+	return &ValidationError{Field: "email", Value: a.String(), Reason: "domain is not available"}
+}
+
+// ParseAddress parses and normalizes an email address.
+// The local part must be an RFC 5322 dot-atom; quoted local parts and IP literal domains
+// are rejected because the API doesn't support them. The domain is mapped as described
+// in UTS #46, which lowercases and normalizes it, and converted to punycode if needed. Errors are returned as *ValidationError.
+func ParseAddress(s string) (Address, error) {
+	invalid := func(reason string) (Address, error) {
+		return Address{}, &ValidationError{Field: "email", Value: s, Reason: reason}
+	}
+
+	at := strings.LastIndexByte(s, '@')
+	if at < 0 {
+		return invalid("missing @")
+	}
+	local, domain := s[:at], s[at+1:]
+
+	if strings.HasPrefix(local, `"`) {
+		return invalid("quoted local parts are not supported")
+	}
+	if reason := checkLocal(local); reason != "" {
+		return invalid(reason)
+	}
+	if strings.HasPrefix(domain, "[") {
+		return invalid("IP literal domains are not supported")
+	}
+	domain, err := normalizeDomain(domain)
+	if err != nil {
+		return invalid(err.Error())
+	}
+
+	a := Address{Local: local, Domain: domain}
+	if len(a.String()) > maxAddressLength {
+		return invalid("address is too long")
+	}
+	return a, nil
+}
+
+// NormalizeAddress parses the email address and returns it in normalized form.
+// See ParseAddress for details.
+func NormalizeAddress(s string) (string, error) {
+	a, err := ParseAddress(s)
+	if err != nil {
+		return "", err
+	}
+	return a.String(), nil
+}
+
+// checkLocal checks that the local part is a dot-atom and returns the reason if it isn't.
+func checkLocal(local string) string {
+	switch {
+	case local == "":
+		return "local part is empty"
+	case len(local) > maxLocalLength:
+		return "local part is too long"
+	case local[0] == '.' || local[len(local)-1] == '.' || strings.Contains(local, ".."):
+		return "local part has a misplaced dot"
+	}
+	for i := 0; i < len(local); i++ {
+		if c := local[i]; c != '.' && !isAtext(c) {
+			return "local part contains an invalid character"
+		}
+	}
+	return ""
+}
+
+// isAtext reports whether c is an RFC 5322 atext character.
+func isAtext(c byte) bool {
+	switch {
+	case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		return true
+	}
+	return strings.IndexByte("!#$%&'*+-/=?^_`{|}~", c) >= 0
+}
+
+// idnaProfile maps internationalized domains as described in UTS #46, including
+// case folding, NFC normalization and the ideographic full stops. The RFC 1034
+// character and hyphen rules are checked by normalizeDomain to report precise reasons.
+var idnaProfile = idna.New(
+	idna.MapForLookup(),
+	idna.BidiRule(),
+	idna.Transitional(false),
+	idna.StrictDomainName(false),
+	idna.CheckHyphens(false),
+)
+
+// normalizeDomain converts the domain to lowercase ASCII, using punycode for internationalized
+// labels, and checks the RFC 5321 length and character limits.
+func normalizeDomain(domain string) (string, error) {
+	if domain == "" {
+		return "", errors.New("domain is empty")
+	}
+	if !utf8.ValidString(domain) {
+		return "", errors.New("domain is not valid UTF-8")
+	}
+	domain, err := idnaProfile.ToASCII(domain)
+	if err != nil {
+		return "", errors.New("domain contains an invalid character")
+	}
+	labels := strings.Split(domain, ".")
+	if len(labels) < 2 {
+		return "", errors.New("domain must have at least two labels")
+	}
+	for _, label := range labels {
+		if label == "" {
+			return "", errors.New("domain has an empty label")
+		}
+		if len(label) > maxLabelLength {
+			return "", errors.New("domain label is too long")
+		}
+		if label[0] == '-' || label[len(label)-1] == '-' {
+			return "", errors.New("domain label starts or ends with a hyphen")
+		}
+		for j := 0; j < len(label); j++ {
+			c := label[j]
+			if !('a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-') {
+				return "", errors.New("domain contains an invalid character")
+			}
+		}
+	}
+	if len(domain) > maxDomainLength {
+		return "", errors.New("domain is too long")
+	}
+	return domain, nil
+}
@@ -0,0 +1,87 @@
+package tempmail
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAddress(t *testing.T) {
+	tests := []struct {
+		name     string
+		address  string
+		expected Address
+	}{
+		{name: "simple", address: "user@example.com", expected: Address{Local: "user", Domain: "example.com"}},
+		{name: "uppercase domain", address: "User.Name@Example.COM", expected: Address{Local: "User.Name", Domain: "example.com"}},
+		{name: "atext characters", address: "a+b!#$%&'*/=?^_`{|}~-c@example.com", expected: Address{Local: "a+b!#$%&'*/=?^_`{|}~-c", Domain: "example.com"}},
+		{name: "IDN", address: "user@München.de", expected: Address{Local: "user", Domain: "xn--mnchen-3ya.de"}},
+		{name: "IDN without ASCII", address: "user@пример.рф", expected: Address{Local: "user", Domain: "xn--e1afmkfd.xn--p1ai"}},
+		{name: "punycode", address: "user@xn--mnchen-3ya.de", expected: Address{Local: "user", Domain: "xn--mnchen-3ya.de"}},
+		{name: "IDN decomposed", address: "user@Mu\u0308nchen.de", expected: Address{Local: "user", Domain: "xn--mnchen-3ya.de"}},
+		{name: "IDN ideographic full stop", address: "user@例え。jp", expected: Address{Local: "user", Domain: "xn--r8jz45g.jp"}},
+		{name: "IDN fullwidth", address: "user@ＥＸＡＭＰＬＥ．ｃｏｍ", expected: Address{Local: "user", Domain: "example.com"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := ParseAddress(tt.address)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, a)
+		})
+	}
+}
+
+func TestParseAddress_invalid(t *testing.T) {
+	tests := []struct {
+		address string
+		reason  string
+	}{
+		{address: "user.example.com", reason: "missing @"},
+		{address: `"quoted"@example.com`, reason: "quoted local parts are not supported"},
+		{address: "@example.com", reason: "local part is empty"},
+		{address: strings.Repeat("a", 65) + "@example.com", reason: "local part is too long"},
+		{address: ".user@example.com", reason: "local part has a misplaced dot"},
+		{address: "user.@example.com", reason: "local part has a misplaced dot"},
+		{address: "us..er@example.com", reason: "local part has a misplaced dot"},
+		{address: "us er@example.com", reason: "local part contains an invalid character"},
+		{address: "usér@example.com", reason: "local part contains an invalid character"},
+		{address: "user@[127.0.0.1]", reason: "IP literal domains are not supported"},
+		{address: "user@", reason: "domain is empty"},
+		{address: "user@localhost", reason: "domain must have at least two labels"},
+		{address: "user@example..com", reason: "domain has an empty label"},
+		{address: "user@example.com.", reason: "domain has an empty label"},
+		{address: "user@" + strings.Repeat("a", 64) + ".com", reason: "domain label is too long"},
+		{address: "user@-example.com", reason: "domain label starts or ends with a hyphen"},
+		{address: "user@exa_mple.com", reason: "domain contains an invalid character"},
+		{address: "user@exa\ue000mple.com", reason: "domain contains an invalid character"},
+		{address: "user@example.com\xff", reason: "domain is not valid UTF-8"},
+		{address: "user@" + strings.Repeat(strings.Repeat("a", 60)+".", 5) + "com", reason: "domain is too long"},
+		{address: strings.Repeat("a", 64) + "@" + strings.Repeat(strings.Repeat("a", 60)+".", 4) + "com", reason: "address is too long"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.reason, func(t *testing.T) {
+			_, err := ParseAddress(tt.address)
+			var validationErr *ValidationError
+			require.ErrorAs(t, err, &validationErr)
+			assert.Equal(t, ValidationError{Field: "email", Value: tt.address, Reason: tt.reason}, *validationErr)
+		})
+	}
+}
+
+func TestNormalizeAddress(t *testing.T) {
+	email, err := NormalizeAddress("User@München.DE")
+	require.NoError(t, err)
+	assert.Equal(t, "User@xn--mnchen-3ya.de", email)
+
+	_, err = NormalizeAddress("invalid")
+	assert.EqualError(t, err, `tempmail: invalid email "invalid": missing @`)
+}
+
+func TestAddress_CheckDomain(t *testing.T) {
+	domains := []ListDomainsDomainResponse{{Name: "example.com", Type: DomainTypePublic}}
+	assert.NoError(t, Address{Local: "user", Domain: "example.com"}.CheckDomain(domains))
+	assert.EqualError(t, Address{Local: "user", Domain: "other.com"}.CheckDomain(domains),
+		`tempmail: invalid email "user@other.com": domain is not available`)
+}
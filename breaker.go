@@ -0,0 +1,225 @@
+package tempmail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	defaultBreakerFailureThreshold = 5
+	defaultBreakerWindow           = 10 * time.Second
+	defaultBreakerCooldown         = 30 * time.Second
+	defaultBreakerHalfOpenRequests = 1
+)
+
+// ErrCircuitOpen is matched by the *CircuitOpenError returned while the circuit breaker is open.
+var ErrCircuitOpen = errors.New("tempmail: circuit breaker is open")
+
+// CircuitOpenError is returned without calling the API while the circuit breaker is open.
+type CircuitOpenError struct {
+	// Until is the time at which the breaker lets a trial call through.
+	Until time.Time
+}
+
+// Error implements the error interface.
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("%s until %s", ErrCircuitOpen, e.Until.Format(time.RFC3339))
+}
+
+// Is reports whether the target is ErrCircuitOpen.
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
+// CircuitState is the state of the circuit breaker.
+type CircuitState int
+
+const (
+	// CircuitClosed lets all calls through.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen fails all calls with ErrCircuitOpen.
+	CircuitOpen
+	// CircuitHalfOpen lets a limited number of trial calls through.
+	CircuitHalfOpen
+)
+
+// String returns the name of the state.
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("CircuitState(%d)", int(s))
+	}
+}
+
+// CircuitBreakerOptions represents the options of the circuit breaker.
+type CircuitBreakerOptions struct {
+	// FailureThreshold is the number of failures within Window that opens the breaker. Defaults to 5.
+	FailureThreshold int
+	// Window is the rolling window in which failures are counted. Defaults to 10 seconds.
+	Window time.Duration
+	// Cooldown is the time the breaker stays open before it lets trial calls through.
+	// Defaults to 30 seconds.
+	Cooldown time.Duration
+	// HalfOpenRequests is the number of trial calls that must succeed to close the breaker.
+	// Defaults to 1.
+	HalfOpenRequests int
+	// OnStateChange is called when the breaker changes its state. Optional.
+	OnStateChange func(from, to CircuitState)
+}
+
+// WithCircuitBreaker makes the Client stop calling the API after repeated failures,
+// so an outage fails fast instead of every call waiting for its timeout.
+// Server errors (5xx) and transport errors count as failures; 4xx errors don't.
+// While the breaker is open, calls fail with a *CircuitOpenError matching ErrCircuitOpen.
+func WithCircuitBreaker(opts CircuitBreakerOptions) ClientOption {
+	return func(c *Client) {
+		c.breaker = newCircuitBreaker(opts)
+	}
+}
+
+// CircuitState returns the state of the circuit breaker.
+// It is always CircuitClosed if WithCircuitBreaker is not used.
+func (c *Client) CircuitState() CircuitState {
+	if c.breaker == nil {
+		return CircuitClosed
+	}
+	return c.breaker.currentState()
+}
+
+// circuitBreaker implements the closed, open and half-open states.
+type circuitBreaker struct {
+	opts CircuitBreakerOptions
+	now  func() time.Time
+
+	mu        sync.Mutex
+	state     CircuitState
+	failures  []time.Time
+	openUntil time.Time
+	trials    int
+	successes int
+}
+
+func newCircuitBreaker(opts CircuitBreakerOptions) *circuitBreaker {
+	if opts.FailureThreshold <= 0 {
+		opts.FailureThreshold = defaultBreakerFailureThreshold
+	}
+	if opts.Window <= 0 {
+		opts.Window = defaultBreakerWindow
+	}
+	if opts.Cooldown <= 0 {
+		opts.Cooldown = defaultBreakerCooldown
+	}
+	if opts.HalfOpenRequests <= 0 {
+		opts.HalfOpenRequests = defaultBreakerHalfOpenRequests
+	}
+	return &circuitBreaker{opts: opts, now: time.Now}
+}
+
+// currentState returns the state, moving from open to half-open once the cooldown has passed.
+func (b *circuitBreaker) currentState() CircuitState {
+	b.mu.Lock()
+	from := b.state
+	to := b.refresh()
+	b.mu.Unlock()
+	b.notify(from, to)
+	return to
+}
+
+// allow returns a *CircuitOpenError if the call must not be sent.
+// Otherwise the caller must pass the outcome of the call to done.
+func (b *circuitBreaker) allow() (done func(failed bool), err error) {
+	b.mu.Lock()
+	from := b.state
+	to := b.refresh()
+	switch {
+	case to == CircuitOpen, to == CircuitHalfOpen && b.trials >= b.opts.HalfOpenRequests:
+		until := b.openUntil
+		b.mu.Unlock()
+		b.notify(from, to)
+		return nil, &CircuitOpenError{Until: until}
+	case to == CircuitHalfOpen:
+		b.trials++
+	}
+	b.mu.Unlock()
+	b.notify(from, to)
+
+	return func(failed bool) { b.record(to, failed) }, nil
+}
+
+// record updates the state with the outcome of a call allowed in the given state.
+func (b *circuitBreaker) record(allowedIn CircuitState, failed bool) {
+	b.mu.Lock()
+	from := b.state
+	now := b.now()
+	switch {
+	case b.state == CircuitHalfOpen && allowedIn == CircuitHalfOpen:
+		if failed {
+			b.open(now)
+		} else if b.successes++; b.successes >= b.opts.HalfOpenRequests {
+			b.state = CircuitClosed
+			b.failures = nil
+		}
+	case b.state == CircuitClosed && failed:
+		b.failures = append(b.failures, now)
+		b.prune(now)
+		if len(b.failures) >= b.opts.FailureThreshold {
+			b.open(now)
+		}
+	}
+	to := b.state
+	b.mu.Unlock()
+	b.notify(from, to)
+}
+
+// refresh moves from open to half-open once the cooldown has passed and returns the state.
+// b.mu must be held.
+func (b *circuitBreaker) refresh() CircuitState {
+	if b.state == CircuitOpen && !b.now().Before(b.openUntil) {
+		b.state = CircuitHalfOpen
+		b.trials, b.successes = 0, 0
+	}
+	return b.state
+}
+
+// open opens the breaker. b.mu must be held.
+func (b *circuitBreaker) open(now time.Time) {
+	b.state = CircuitOpen
+	b.openUntil = now.Add(b.opts.Cooldown)
+	b.failures = nil
+}
+
+// prune drops the failures outside the window. b.mu must be held.
+func (b *circuitBreaker) prune(now time.Time) {
+	start := now.Add(-b.opts.Window)
+	i := 0
+	for i < len(b.failures) && !b.failures[i].After(start) {
+		i++
+	}
+	b.failures = b.failures[i:]
+}
+
+// notify calls OnStateChange if the state changed. It must be called without b.mu held,
+// so the callback can inspect the Client.
+func (b *circuitBreaker) notify(from, to CircuitState) {
+	if from != to && b.opts.OnStateChange != nil {
+		b.opts.OnStateChange(from, to)
+	}
+}
+
+// isBreakerFailure reports whether the outcome of a call counts as a failure for the breaker:
+// a transport error, other than the caller canceling the call, or a server error.
+func isBreakerFailure(transportErr error, statusCode int) bool {
+	if transportErr != nil {
+		return !errors.Is(transportErr, context.Canceled)
+	}
+	return statusCode >= 500
+}
@@ -0,0 +1,155 @@
+package tempmail
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newBreakerTestClient creates a Client with a circuit breaker whose clock is moved by the returned function.
+func newBreakerTestClient(t *testing.T, opts CircuitBreakerOptions) (*Client, *mockDoer, func(time.Duration)) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewClient("API_KEY", nil, WithCircuitBreaker(opts))
+	c.breaker.now = func() time.Time { return now }
+	mDoer := newMockDoer(t)
+	c.doer = mDoer
+	return c, mDoer, func(d time.Duration) { now = now.Add(d) }
+}
+
+// statusResponse returns a mock response function with the status code.
+func statusResponse(statusCode int) func(*http.Request) (*http.Response, error) {
+	return func(*http.Request) (*http.Response, error) {
+		if statusCode >= 400 {
+			return newTestResponse(statusCode, []byte(`{"error":{"type":"api_error","code":"error","detail":"error"}}`)), nil
+		}
+		return newTestResponse(statusCode, []byte(`{"domains":[]}`)), nil
+	}
+}
+
+func listDomainsErr(c *Client) error {
+	_, _, err := c.ListDomains(context.Background())
+	return err
+}
+
+func TestClient_circuitBreaker(t *testing.T) {
+	t.Run("opens after failures", func(t *testing.T) {
+		var changes []string
+		c, mDoer, _ := newBreakerTestClient(t, CircuitBreakerOptions{
+			FailureThreshold: 3,
+			OnStateChange: func(from, to CircuitState) {
+				changes = append(changes, from.String()+"->"+to.String())
+			},
+		})
+		mDoer.EXPECT().Do(mock.Anything).RunAndReturn(statusResponse(http.StatusServiceUnavailable)).Times(3)
+
+		for i := 0; i < 3; i++ {
+			var httpErr *HTTPError
+			require.ErrorAs(t, listDomainsErr(c), &httpErr)
+		}
+		assert.Equal(t, CircuitOpen, c.CircuitState())
+
+		err := listDomainsErr(c)
+		assert.ErrorIs(t, err, ErrCircuitOpen)
+		var openErr *CircuitOpenError
+		require.ErrorAs(t, err, &openErr)
+		assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 30, 0, time.UTC), openErr.Until)
+		assert.Equal(t, []string{"closed->open"}, changes)
+	})
+
+	t.Run("4xx errors don't count", func(t *testing.T) {
+		c, mDoer, _ := newBreakerTestClient(t, CircuitBreakerOptions{FailureThreshold: 2})
+		mDoer.EXPECT().Do(mock.Anything).RunAndReturn(statusResponse(http.StatusBadRequest)).Times(5)
+
+		for i := 0; i < 5; i++ {
+			assert.NotErrorIs(t, listDomainsErr(c), ErrCircuitOpen)
+		}
+		assert.Equal(t, CircuitClosed, c.CircuitState())
+	})
+
+	t.Run("transport errors count", func(t *testing.T) {
+		c, mDoer, _ := newBreakerTestClient(t, CircuitBreakerOptions{FailureThreshold: 2})
+		mDoer.EXPECT().Do(mock.Anything).Return(nil, context.Canceled).Once()
+		mDoer.EXPECT().Do(mock.Anything).Return(nil, assert.AnError).Twice()
+
+		for i := 0; i < 3; i++ {
+			assert.Error(t, listDomainsErr(c))
+		}
+		assert.Equal(t, CircuitOpen, c.CircuitState())
+	})
+
+	t.Run("rolling window", func(t *testing.T) {
+		c, mDoer, advance := newBreakerTestClient(t, CircuitBreakerOptions{FailureThreshold: 2, Window: time.Minute})
+		mDoer.EXPECT().Do(mock.Anything).RunAndReturn(statusResponse(http.StatusInternalServerError)).Times(3)
+
+		assert.Error(t, listDomainsErr(c))
+		advance(time.Minute)
+		assert.Error(t, listDomainsErr(c))
+		assert.Equal(t, CircuitClosed, c.CircuitState())
+		advance(time.Second)
+		assert.Error(t, listDomainsErr(c))
+		assert.Equal(t, CircuitOpen, c.CircuitState())
+	})
+
+	t.Run("half-open", func(t *testing.T) {
+		var changes []string
+		c, mDoer, advance := newBreakerTestClient(t, CircuitBreakerOptions{
+			FailureThreshold: 1,
+			Cooldown:         time.Minute,
+			HalfOpenRequests: 2,
+			OnStateChange: func(from, to CircuitState) {
+				changes = append(changes, from.String()+"->"+to.String())
+			},
+		})
+		mDoer.EXPECT().Do(mock.Anything).RunAndReturn(statusResponse(http.StatusInternalServerError)).Once()
+		assert.Error(t, listDomainsErr(c))
+		advance(time.Minute)
+
+		// A failed trial opens the breaker again.
+		mDoer.EXPECT().Do(mock.Anything).RunAndReturn(statusResponse(http.StatusInternalServerError)).Once()
+		assert.Error(t, listDomainsErr(c))
+		assert.ErrorIs(t, listDomainsErr(c), ErrCircuitOpen)
+		advance(time.Minute)
+
+		// All trials must succeed to close the breaker.
+		mDoer.EXPECT().Do(mock.Anything).RunAndReturn(statusResponse(http.StatusOK)).Twice()
+		assert.NoError(t, listDomainsErr(c))
+		assert.Equal(t, CircuitHalfOpen, c.CircuitState())
+		assert.NoError(t, listDomainsErr(c))
+		assert.Equal(t, CircuitClosed, c.CircuitState())
+
+		assert.Equal(t, []string{
+			"closed->open", "open->half-open", "half-open->open",
+			"open->half-open", "half-open->closed",
+		}, changes)
+	})
+
+	t.Run("half-open limits trial calls", func(t *testing.T) {
+		c, mDoer, advance := newBreakerTestClient(t, CircuitBreakerOptions{FailureThreshold: 1, Cooldown: time.Minute})
+		mDoer.EXPECT().Do(mock.Anything).RunAndReturn(statusResponse(http.StatusInternalServerError)).Once()
+		assert.Error(t, listDomainsErr(c))
+		advance(time.Minute)
+
+		done, err := c.breaker.allow()
+		require.NoError(t, err)
+		_, err = c.breaker.allow()
+		assert.ErrorIs(t, err, ErrCircuitOpen)
+		done(false)
+		assert.Equal(t, CircuitClosed, c.CircuitState())
+	})
+}
+
+func TestClient_CircuitState(t *testing.T) {
+	assert.Equal(t, CircuitClosed, newClient().CircuitState())
+}
+
+func TestCircuitState_String(t *testing.T) {
+	assert.Equal(t, "closed", CircuitClosed.String())
+	assert.Equal(t, "open", CircuitOpen.String())
+	assert.Equal(t, "half-open", CircuitHalfOpen.String())
+	assert.Equal(t, "CircuitState(7)", CircuitState(7).String())
+}
@@ -0,0 +1,217 @@
+package tempmail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultBulkConcurrency is the default number of concurrent calls of a bulk operation.
+const DefaultBulkConcurrency = 4
+
+// ErrSkipped is the error of the items of a bulk operation that were not attempted
+// because it stopped on a fatal error.
+var ErrSkipped = errors.New("tempmail: skipped after a fatal error")
+
+// BulkOptions represents the options of a bulk operation.
+type BulkOptions struct {
+	// Concurrency is the maximum number of concurrent calls. Defaults to 4.
+	Concurrency int
+	// StopOnFatal stops the operation on the first fatal error, such as 401 Unauthorized
+	// or 403 Forbidden, after which every call would fail. The remaining items fail with ErrSkipped.
+	StopOnFatal bool
+}
+
+// BulkResult is the result of one item of DeleteEmails or DeleteMessages.
+type BulkResult struct {
+	// ID is the email address or message ID.
+	ID string
+	// Response is the response of the call, if any.
+	Response *Response
+	// Err is the error of the call.
+	Err error
+}
+
+// MessageResult is the result of one item of GetMessages.
+type MessageResult struct {
+	// ID is the message ID.
+	ID string
+	// Message is the fetched message.
+	Message Message
+	// Response is the response of the call, if any.
+	Response *Response
+	// Err is the error of the call.
+	Err error
+}
+
+// DeleteEmails deletes the email addresses concurrently.
+// The results are in the order of emails. The returned error joins the errors of all
+// attempted items, each prefixed with its email address.
+func (c *Client) DeleteEmails(ctx context.Context, emails []string, opts BulkOptions) ([]BulkResult, error) {
+	results := make([]BulkResult, len(emails))
+	err := c.bulk(ctx, len(emails), opts, func(ctx context.Context, i int) error {
+		r, err := c.DeleteEmail(ctx, emails[i])
+		results[i] = BulkResult{ID: emails[i], Response: r, Err: err}
+		return err
+	}, func(i int, err error) {
+		results[i] = BulkResult{ID: emails[i], Err: err}
+	}, func(i int) string { return emails[i] })
+	return results, err
+}
+
+// DeleteMessages deletes the messages concurrently.
+// The results are in the order of messageIDs. The returned error joins the errors of all
+// attempted items, each prefixed with its message ID.
+func (c *Client) DeleteMessages(ctx context.Context, messageIDs []string, opts BulkOptions) ([]BulkResult, error) {
+	results := make([]BulkResult, len(messageIDs))
+	err := c.bulk(ctx, len(messageIDs), opts, func(ctx context.Context, i int) error {
+		r, err := c.DeleteMessage(ctx, messageIDs[i])
+		results[i] = BulkResult{ID: messageIDs[i], Response: r, Err: err}
+		return err
+	}, func(i int, err error) {
+		results[i] = BulkResult{ID: messageIDs[i], Err: err}
+	}, func(i int) string { return messageIDs[i] })
+	return results, err
+}
+
+// GetMessages gets the messages concurrently.
+// The results are in the order of messageIDs. The returned error joins the errors of all
+// attempted items, each prefixed with its message ID.
+func (c *Client) GetMessages(ctx context.Context, messageIDs []string, opts BulkOptions) ([]MessageResult, error) {
+	results := make([]MessageResult, len(messageIDs))
+	err := c.bulk(ctx, len(messageIDs), opts, func(ctx context.Context, i int) error {
+		m, r, err := c.GetMessage(ctx, messageIDs[i])
+		results[i] = MessageResult{ID: messageIDs[i], Message: m, Response: r, Err: err}
+		return err
+	}, func(i int, err error) {
+		results[i] = MessageResult{ID: messageIDs[i], Err: err}
+	}, func(i int) string { return messageIDs[i] })
+	return results, err
+}
+
+// bulk calls do for the n items with bounded concurrency, waiting for the rate limit
+// before every call. Items that are not attempted are passed to skip.
+func (c *Client) bulk(
+	ctx context.Context,
+	n int,
+	opts BulkOptions,
+	do func(ctx context.Context, i int) error,
+	skip func(i int, err error),
+	id func(i int) string,
+) error {
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultBulkConcurrency
+	}
+
+	var (
+		wg   sync.WaitGroup
+		errs = make([]error, n)
+		stop = make(chan struct{})
+		once sync.Once
+		sem  = make(chan struct{}, concurrency)
+	)
+	fail := func(i int, err error) {
+		errs[i] = fmt.Errorf("%s: %w", id(i), err)
+	}
+
+	for i := 0; i < n; i++ {
+		select {
+		case <-stop:
+			skip(i, ErrSkipped)
+			continue
+		case <-ctx.Done():
+			skip(i, ctx.Err())
+			fail(i, ctx.Err())
+			continue
+		case sem <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			select {
+			case <-stop:
+				skip(i, ErrSkipped)
+				return
+			case <-ctx.Done():
+				skip(i, ctx.Err())
+				fail(i, ctx.Err())
+				return
+			default:
+			}
+			if err := c.limit.wait(ctx); err != nil {
+				skip(i, err)
+				fail(i, err)
+				return
+			}
+			if err := do(ctx, i); err != nil {
+				fail(i, err)
+				if opts.StopOnFatal && isFatal(err) {
+					once.Do(func() { close(stop) })
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// isFatal reports whether the error means every further call would fail too.
+func isFatal(err error) bool {
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || httpErr.Response == nil {
+		return false
+	}
+	return httpErr.Response.StatusCode == http.StatusUnauthorized || httpErr.Response.StatusCode == http.StatusForbidden
+}
+
+// rateLimiter delays calls while the rate limit reported by the last response is exhausted.
+// It is shared by all calls of a Client.
+type rateLimiter struct {
+	mu    sync.Mutex
+	rate  Rate
+	now   func() time.Time
+	after func(time.Duration) <-chan time.Time
+}
+
+func newRateLimiter(now func() time.Time) *rateLimiter {
+	return &rateLimiter{now: now, after: time.After}
+}
+
+// update records the rate limit of a response.
+func (l *rateLimiter) update(rate Rate) {
+	if rate.Limit == 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.rate = rate
+}
+
+// wait blocks until the rate limit resets if no calls remain.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	rate := l.rate
+	l.mu.Unlock()
+
+	if rate.Limit == 0 || rate.Remaining > 0 || rate.Reset.IsZero() {
+		return nil
+	}
+	d := rate.Reset.Sub(l.now())
+	if d <= 0 {
+		return nil
+	}
+	select {
+	case <-l.after(d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
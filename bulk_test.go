@@ -0,0 +1,160 @@
+package tempmail
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_DeleteEmails(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		emails := make([]string, 20)
+		for i := range emails {
+			emails[i] = fmt.Sprintf("user%d@example.com", i)
+		}
+
+		var inFlight, maxInFlight atomic.Int32
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(mock.Anything).RunAndReturn(func(req *http.Request) (*http.Response, error) {
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				m := maxInFlight.Load()
+				if n <= m || maxInFlight.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			return newTestResponse(http.StatusOK, nil), nil
+		}).Times(len(emails))
+
+		c := newClient()
+		c.doer = mDoer
+		results, err := c.DeleteEmails(context.Background(), emails, BulkOptions{Concurrency: 3})
+		require.NoError(t, err)
+		require.Len(t, results, len(emails))
+		for i, r := range results {
+			assert.Equal(t, emails[i], r.ID)
+			assert.NoError(t, r.Err)
+			assert.Equal(t, http.StatusOK, r.Response.StatusCode)
+		}
+		assert.LessOrEqual(t, maxInFlight.Load(), int32(3))
+	})
+
+	t.Run("errors are joined", func(t *testing.T) {
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(isRequest(http.MethodDelete, "/v1/emails/a@example.com")).Return(newTestResponse(http.StatusOK, nil), nil)
+		mDoer.EXPECT().Do(isRequest(http.MethodDelete, "/v1/emails/b@example.com")).
+			Return(newTestResponse(http.StatusBadRequest, readFile(t, "testdata/error_response.json")), nil)
+
+		c := newClient()
+		c.doer = mDoer
+		results, err := c.DeleteEmails(context.Background(), []string{"a@example.com", "b@example.com", "invalid"}, BulkOptions{})
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "b@example.com: ")
+		assert.ErrorContains(t, err, "invalid: tempmail: invalid email")
+
+		assert.NoError(t, results[0].Err)
+		var httpErr *HTTPError
+		assert.ErrorAs(t, results[1].Err, &httpErr)
+		var validationErr *ValidationError
+		assert.ErrorAs(t, results[2].Err, &validationErr)
+	})
+
+	t.Run("stop on fatal", func(t *testing.T) {
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(mock.Anything).
+			Return(newTestResponse(http.StatusUnauthorized, readFile(t, "testdata/error_response.json")), nil).Once()
+
+		c := newClient()
+		c.doer = mDoer
+		emails := []string{"a@example.com", "b@example.com", "c@example.com"}
+		results, err := c.DeleteEmails(context.Background(), emails, BulkOptions{Concurrency: 1, StopOnFatal: true})
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrSkipped)
+
+		var httpErr *HTTPError
+		assert.ErrorAs(t, results[0].Err, &httpErr)
+		for _, r := range results[1:] {
+			assert.ErrorIs(t, r.Err, ErrSkipped)
+		}
+	})
+
+	t.Run("context canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		c := newClient()
+		c.doer = newMockDoer(t)
+		results, err := c.DeleteEmails(ctx, []string{"a@example.com"}, BulkOptions{})
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.ErrorIs(t, results[0].Err, context.Canceled)
+	})
+}
+
+func TestClient_DeleteMessages(t *testing.T) {
+	mDoer := newMockDoer(t)
+	mDoer.EXPECT().Do(isRequest(http.MethodDelete, "/v1/messages/1")).Return(newTestResponse(http.StatusOK, nil), nil)
+	mDoer.EXPECT().Do(isRequest(http.MethodDelete, "/v1/messages/2")).Return(newTestResponse(http.StatusOK, nil), nil)
+
+	c := newClient()
+	c.doer = mDoer
+	results, err := c.DeleteMessages(context.Background(), []string{"1", "2"}, BulkOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "1", results[0].ID)
+	assert.Equal(t, "2", results[1].ID)
+}
+
+func TestClient_GetMessages(t *testing.T) {
+	mDoer := newMockDoer(t)
+	mDoer.EXPECT().Do(isRequest(http.MethodGet, "/v1/messages/"+testMessageID)).
+		Return(newTestResponse(http.StatusOK, readFile(t, "testdata/get_message.json")), nil)
+	mDoer.EXPECT().Do(isRequest(http.MethodGet, "/v1/messages/missing")).
+		Return(newTestResponse(http.StatusNotFound, readFile(t, "testdata/error_response.json")), nil)
+
+	c := newClient()
+	c.doer = mDoer
+	results, err := c.GetMessages(context.Background(), []string{testMessageID, "missing"}, BulkOptions{})
+	assert.ErrorContains(t, err, "missing: ")
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, "Test Message", results[0].Message.Subject)
+	assert.Error(t, results[1].Err)
+	assert.Equal(t, "missing", results[1].ID)
+}
+
+func TestRateLimiter_wait(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := newRateLimiter(func() time.Time { return now })
+	var waited time.Duration
+	fire := make(chan time.Time)
+	l.after = func(d time.Duration) <-chan time.Time {
+		waited = d
+		return fire
+	}
+
+	require.NoError(t, l.wait(context.Background()))
+
+	l.update(Rate{Limit: 10, Remaining: 1, Reset: now.Add(time.Minute)})
+	require.NoError(t, l.wait(context.Background()))
+	assert.Zero(t, waited)
+
+	l.update(Rate{Limit: 10, Remaining: 0, Reset: now.Add(time.Minute)})
+	go func() { fire <- now }()
+	require.NoError(t, l.wait(context.Background()))
+	assert.Equal(t, time.Minute, waited)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, l.wait(ctx), context.Canceled)
+
+	// Responses without rate limit headers don't reset the state.
+	l.update(Rate{})
+	assert.ErrorIs(t, l.wait(ctx), context.Canceled)
+}
@@ -0,0 +1,528 @@
+package tempmail
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrCacheMiss is returned by Cache.Get when the key is not in the cache.
+var ErrCacheMiss = errors.New("tempmail: cache miss")
+
+// DefaultCacheEntries is the default capacity of an LRUCache.
+const DefaultCacheEntries = 1000
+
+const (
+	headerETag        = "ETag"
+	headerIfNoneMatch = "If-None-Match"
+)
+
+// CacheEntry is a cached response body.
+type CacheEntry struct {
+	// Body is the raw JSON response body.
+	Body []byte `json:"body"`
+	// ETag is the entity tag sent by the server, if any.
+	ETag string `json:"etag,omitempty"`
+}
+
+// Cache stores the responses of GetMessage and GetMessageSourceCode, which never change.
+// Keys are escaped request paths, so they contain the escaped message ID.
+// Implementations must be safe for concurrent use.
+type Cache interface {
+	// Get returns the cached entry or ErrCacheMiss.
+	Get(ctx context.Context, key string) (CacheEntry, error)
+	// Set creates or replaces the cached entry.
+	Set(ctx context.Context, key string, entry CacheEntry) error
+	// Delete removes the entry from the cache. Deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// WithCache caches the responses of GetMessage and GetMessageSourceCode.
+// Cached entries are returned without a request. The API doesn't document ETags, but if
+// a server sends one, the entry is revalidated with If-None-Match and the cached body is
+// used on 304 Not Modified.
+// DeleteMessage removes the entries of the message. DeleteEmail removes the entries of
+// the messages of the address that the client has listed or fetched, also when the address
+// has expired or is not found.
+// Cache errors are treated as misses and don't fail the call.
+func WithCache(cache Cache) ClientOption {
+	return func(c *Client) {
+		c.cache = cache
+		limit := DefaultCacheEntries
+		if lru, ok := cache.(*LRUCache); ok {
+			limit = lru.capacity
+			lru.onEvict(c.evicted)
+		}
+		c.cached = newCacheIndex(limit)
+	}
+}
+
+// cacheIndex tracks the IDs of the cached messages of every email address,
+// so DeleteEmail can invalidate them. It holds at most limit pairs of address and message.
+type cacheIndex struct {
+	mu    sync.Mutex
+	limit int
+	// emails maps the index key of an address to its messages.
+	emails map[string]*indexedEmail
+	// messages maps a message ID to the index keys of its addresses.
+	messages map[string]map[string]struct{}
+	// order holds the indexedMessage values, oldest first.
+	order *list.List
+}
+
+// indexedEmail is an email address in the cacheIndex.
+type indexedEmail struct {
+	// expiresAt is the time at which the address expires, or zero if unknown.
+	expiresAt time.Time
+	// ids maps the IDs of the messages to their elements in cacheIndex.order.
+	ids map[string]*list.Element
+}
+
+// indexedMessage is a message of an address in the cacheIndex.
+type indexedMessage struct {
+	id    string
+	email string
+}
+
+func newCacheIndex(limit int) *cacheIndex {
+	return &cacheIndex{
+		limit:    limit,
+		emails:   make(map[string]*indexedEmail),
+		messages: make(map[string]map[string]struct{}),
+		order:    list.New(),
+	}
+}
+
+// add records that the messages belong to the email address, which expires at expiresAt
+// unless it is zero. It returns the IDs of the messages dropped from the index because
+// their address has expired or the index is full; their cache entries must be removed,
+// since they could no longer be invalidated.
+func (i *cacheIndex) add(email string, expiresAt, now time.Time, messageIDs ...string) []string {
+	if email == "" || len(messageIDs) == 0 {
+		return nil
+	}
+	key := indexKey(email)
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	var dropped []string
+	for k, e := range i.emails {
+		if !e.expiresAt.IsZero() && !now.Before(e.expiresAt) {
+			dropped = append(dropped, i.removeLocked(k)...)
+		}
+	}
+
+	e, ok := i.emails[key]
+	if !ok {
+		e = &indexedEmail{ids: make(map[string]*list.Element)}
+		i.emails[key] = e
+	}
+	if !expiresAt.IsZero() {
+		e.expiresAt = expiresAt
+	}
+	for _, id := range messageIDs {
+		if el, ok := e.ids[id]; ok {
+			i.order.MoveToBack(el)
+			continue
+		}
+		e.ids[id] = i.order.PushBack(indexedMessage{id: id, email: key})
+		if i.messages[id] == nil {
+			i.messages[id] = make(map[string]struct{})
+		}
+		i.messages[id][key] = struct{}{}
+	}
+
+	for i.order.Len() > i.limit {
+		id := i.order.Front().Value.(indexedMessage).id
+		i.forgetLocked(id)
+		dropped = append(dropped, id)
+	}
+	return dropped
+}
+
+// remove forgets the email address and returns the IDs of its messages.
+func (i *cacheIndex) remove(email string) []string {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.removeLocked(indexKey(email))
+}
+
+// removeLocked forgets the address with the index key and returns the IDs of its messages.
+// i.mu must be held.
+func (i *cacheIndex) removeLocked(key string) []string {
+	e, ok := i.emails[key]
+	if !ok {
+		return nil
+	}
+	ids := make([]string, 0, len(e.ids))
+	for id, el := range e.ids {
+		ids = append(ids, id)
+		i.order.Remove(el)
+		delete(i.messages[id], key)
+		if len(i.messages[id]) == 0 {
+			delete(i.messages, id)
+		}
+	}
+	delete(i.emails, key)
+	return ids
+}
+
+// forget removes the messages from the index.
+func (i *cacheIndex) forget(messageIDs ...string) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	for _, id := range messageIDs {
+		i.forgetLocked(id)
+	}
+}
+
+// forgetLocked removes the message from all addresses in the index. i.mu must be held.
+func (i *cacheIndex) forgetLocked(id string) {
+	for key := range i.messages[id] {
+		e := i.emails[key]
+		i.order.Remove(e.ids[id])
+		delete(e.ids, id)
+		if len(e.ids) == 0 {
+			delete(i.emails, key)
+		}
+	}
+	delete(i.messages, id)
+}
+
+// len returns the number of indexed messages.
+func (i *cacheIndex) len() int {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.order.Len()
+}
+
+// indexKey returns the key of an email address in the cacheIndex, so that the To header
+// of a message matches the address passed to DeleteEmail despite a display name or case.
+func indexKey(email string) string {
+	if a, err := mail.ParseAddress(email); err == nil {
+		email = a.Address
+	}
+	if normalized, err := NormalizeAddress(email); err == nil {
+		email = normalized
+	}
+	return strings.ToLower(email)
+}
+
+// doCached sends a GET request through the cache and decodes the response into v.
+func (c *Client) doCached(req *http.Request, v interface{}) (*Response, error) {
+	if c.cache == nil {
+		return c.do(req, v)
+	}
+	ctx := req.Context()
+	op := operationFromContext(ctx)
+	key := cacheKey(req.URL)
+
+	var entry CacheEntry
+	hit := false
+	if !c.call.noCache {
+		var err error
+		entry, err = c.cache.Get(ctx, key)
+		hit = err == nil
+	}
+	if hit && entry.ETag == "" {
+		if err := c.decodeBytes(op, entry.Body, v); err == nil {
+			return cachedResponse(req, entry), nil
+		}
+		hit = false
+	}
+	if hit {
+		req.Header.Set(headerIfNoneMatch, entry.ETag)
+	}
+
+	r, err := c.send(req, func(r *Response) error {
+		if hit && r.StatusCode == http.StatusNotModified {
+			r.Cached = true
+			return c.decodeBytes(op, entry.Body, v)
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			return err
+		}
+		if err := c.decodeBytes(op, body, v); err != nil {
+			return err
+		}
+		_ = c.cache.Set(ctx, key, CacheEntry{Body: body, ETag: r.Header.Get(headerETag)})
+		return nil
+	})
+	if isNotFound(err) {
+		_ = c.cache.Delete(ctx, key)
+	}
+	return r, err
+}
+
+// cachedResponse returns the Response of a cache hit served without a request.
+func cachedResponse(req *http.Request, entry CacheEntry) *Response {
+	header := make(http.Header)
+	if entry.ETag != "" {
+		header.Set(headerETag, entry.ETag)
+	}
+	return &Response{
+		Response: &http.Response{
+			Status:     "200 OK",
+			StatusCode: http.StatusOK,
+			Header:     header,
+			Body:       http.NoBody,
+			Request:    req,
+		},
+		Cached: true,
+	}
+}
+
+// invalidateMessages removes the cached responses of the messages.
+func (c *Client) invalidateMessages(ctx context.Context, messageIDs ...string) {
+	if c.cache == nil {
+		return
+	}
+	c.cached.forget(messageIDs...)
+	for _, id := range messageIDs {
+		for _, path := range []string{buildPath("v1", "messages", id), buildPath("v1", "messages", id, "source")} {
+			if u, err := url.Parse(path); err == nil {
+				_ = c.cache.Delete(ctx, cacheKey(u))
+			}
+		}
+	}
+}
+
+// cacheKey returns the cache key of a request URL. The path is kept escaped, so that
+// the message ID "abc/source" doesn't share the key of the source of the message "abc".
+func cacheKey(u *url.URL) string {
+	return u.EscapedPath()
+}
+
+// indexMessages records that the messages belong to the email address, and removes
+// the cache entries of the messages dropped from the index.
+func (c *Client) indexMessages(ctx context.Context, email string, messages ...Message) {
+	if c.cache == nil {
+		return
+	}
+	ids := make([]string, 0, len(messages))
+	for _, m := range messages {
+		ids = append(ids, m.ID)
+	}
+	address := email
+	if a, err := mail.ParseAddress(email); err == nil {
+		address = a.Address
+	}
+	expiresAt, _ := c.known.expiry(address)
+	c.invalidateMessages(ctx, c.cached.add(email, expiresAt, c.now(), ids...)...)
+}
+
+// invalidateEmail removes the cached responses of the messages of the email address.
+func (c *Client) invalidateEmail(ctx context.Context, email string) {
+	if c.cache == nil {
+		return
+	}
+	c.invalidateMessages(ctx, c.cached.remove(email)...)
+}
+
+// evicted is called with the key of an entry evicted from an LRUCache. The other entry of
+// the message is removed too, since it could no longer be invalidated.
+func (c *Client) evicted(key string) {
+	if id, ok := messageIDFromKey(key); ok {
+		c.invalidateMessages(context.Background(), id)
+	}
+}
+
+// messageIDFromKey returns the message ID of the cache key of GetMessage or GetMessageSourceCode.
+func messageIDFromKey(key string) (string, bool) {
+	rest, ok := strings.CutPrefix(key, "/v1/messages/")
+	if !ok {
+		return "", false
+	}
+	escaped, _, _ := strings.Cut(rest, "/")
+	id, err := url.PathUnescape(escaped)
+	return id, err == nil
+}
+
+// isNotFound reports whether the error is an HTTP 404 response.
+func isNotFound(err error) bool {
+	var httpErr *HTTPError
+	return errors.As(err, &httpErr) && httpErr.Response != nil && httpErr.Response.StatusCode == http.StatusNotFound
+}
+
+// LRUCache is an in-memory Cache that evicts the least recently used entries.
+type LRUCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	entries  map[string]*list.Element
+	// evictHooks are called with the key of every evicted entry.
+	evictHooks []func(key string)
+}
+
+// lruItem is an element of LRUCache.order.
+type lruItem struct {
+	key   string
+	entry CacheEntry
+}
+
+// NewLRUCache creates an LRUCache holding up to capacity entries.
+// If capacity is not positive, DefaultCacheEntries is used.
+func NewLRUCache(capacity int) *LRUCache {
+	if capacity <= 0 {
+		capacity = DefaultCacheEntries
+	}
+	return &LRUCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// Get implements Cache.
+func (c *LRUCache) Get(_ context.Context, key string) (CacheEntry, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		return CacheEntry{}, ErrCacheMiss
+	}
+	c.order.MoveToFront(e)
+	return copyCacheEntry(e.Value.(*lruItem).entry), nil
+}
+
+// Set implements Cache.
+func (c *LRUCache) Set(_ context.Context, key string, entry CacheEntry) error {
+	c.mu.Lock()
+	entry = copyCacheEntry(entry)
+	if e, ok := c.entries[key]; ok {
+		e.Value.(*lruItem).entry = entry
+		c.order.MoveToFront(e)
+		c.mu.Unlock()
+		return nil
+	}
+	c.entries[key] = c.order.PushFront(&lruItem{key: key, entry: entry})
+	var evicted []string
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruItem).key)
+		evicted = append(evicted, oldest.Value.(*lruItem).key)
+	}
+	hooks := c.evictHooks
+	c.mu.Unlock()
+
+	// The hooks may use the cache, so they are called without holding the lock.
+	for _, key := range evicted {
+		for _, hook := range hooks {
+			hook(key)
+		}
+	}
+	return nil
+}
+
+// onEvict registers a function that is called with the key of every evicted entry.
+func (c *LRUCache) onEvict(hook func(key string)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.evictHooks = append(c.evictHooks, hook)
+}
+
+// Delete implements Cache.
+func (c *LRUCache) Delete(_ context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.entries[key]; ok {
+		c.order.Remove(e)
+		delete(c.entries, key)
+	}
+	return nil
+}
+
+// Len returns the number of cached entries.
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+func copyCacheEntry(e CacheEntry) CacheEntry {
+	e.Body = append([]byte(nil), e.Body...)
+	return e
+}
+
+// DiskCache is a Cache that stores every entry as a JSON file in a directory,
+// so it can be shared across process restarts. Entries are never evicted.
+type DiskCache struct {
+	dir string
+}
+
+// NewDiskCache creates a DiskCache in the directory. The directory is created on the first Set.
+func NewDiskCache(dir string) *DiskCache {
+	return &DiskCache{dir: dir}
+}
+
+// Get implements Cache.
+func (c *DiskCache) Get(_ context.Context, key string) (CacheEntry, error) {
+	b, err := os.ReadFile(c.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return CacheEntry{}, ErrCacheMiss
+	}
+	if err != nil {
+		return CacheEntry{}, err
+	}
+	var entry CacheEntry
+	if err := json.Unmarshal(b, &entry); err != nil {
+		return CacheEntry{}, fmt.Errorf("tempmail: decode cache entry %s: %w", key, err)
+	}
+	return entry, nil
+}
+
+// Set implements Cache.
+func (c *DiskCache) Set(_ context.Context, key string, entry CacheEntry) error {
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.dir, 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(c.dir, "entry.*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path(key))
+}
+
+// Delete implements Cache.
+func (c *DiskCache) Delete(_ context.Context, key string) error {
+	err := os.Remove(c.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// path returns the file of the key. Keys are hashed, so they are safe file names.
+func (c *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
@@ -0,0 +1,373 @@
+package tempmail
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testMessageID   = "01JE97FT950QRPDYGDXJ4R43QR"
+	testMessagePath = "/v1/messages/" + testMessageID
+)
+
+// newETagResponse creates a response with the ETag header.
+func newETagResponse(statusCode int, body []byte, etag string) *http.Response {
+	r := newTestResponse(statusCode, body)
+	r.Header = make(http.Header)
+	r.Header.Set(headerETag, etag)
+	return r
+}
+
+func TestClient_GetMessage_cache(t *testing.T) {
+	t.Run("hit without ETag", func(t *testing.T) {
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(isRequest(http.MethodGet, testMessagePath)).
+			Return(newTestResponse(http.StatusOK, readFile(t, "testdata/get_message.json")), nil).Once()
+
+		c := NewClient("test-api-key", nil, WithCache(NewLRUCache(0)))
+		c.doer = mDoer
+
+		first, resp, err := c.GetMessage(context.Background(), testMessageID)
+		require.NoError(t, err)
+		assert.False(t, resp.Cached)
+
+		second, resp, err := c.GetMessage(context.Background(), testMessageID)
+		require.NoError(t, err)
+		assert.True(t, resp.Cached)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, first, second)
+	})
+
+	t.Run("revalidate with ETag", func(t *testing.T) {
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(mock.MatchedBy(func(req *http.Request) bool {
+			return req.Header.Get(headerIfNoneMatch) == ""
+		})).Return(newETagResponse(http.StatusOK, readFile(t, "testdata/get_message.json"), `"v1"`), nil).Once()
+		mDoer.EXPECT().Do(mock.MatchedBy(func(req *http.Request) bool {
+			return req.Header.Get(headerIfNoneMatch) == `"v1"`
+		})).Return(newETagResponse(http.StatusNotModified, nil, `"v1"`), nil).Once()
+
+		c := NewClient("test-api-key", nil, WithCache(NewLRUCache(0)))
+		c.doer = mDoer
+
+		first, _, err := c.GetMessage(context.Background(), testMessageID)
+		require.NoError(t, err)
+		second, resp, err := c.GetMessage(context.Background(), testMessageID)
+		require.NoError(t, err)
+		assert.True(t, resp.Cached)
+		assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+		assert.Equal(t, first, second)
+	})
+
+	t.Run("ETag changed", func(t *testing.T) {
+		cache := NewLRUCache(0)
+		require.NoError(t, cache.Set(context.Background(), testMessagePath, CacheEntry{Body: []byte(`{"id":"old"}`), ETag: `"v1"`}))
+
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(mock.Anything).
+			Return(newETagResponse(http.StatusOK, readFile(t, "testdata/get_message.json"), `"v2"`), nil).Once()
+
+		c := NewClient("test-api-key", nil, WithCache(cache))
+		c.doer = mDoer
+		result, resp, err := c.GetMessage(context.Background(), testMessageID)
+		require.NoError(t, err)
+		assert.False(t, resp.Cached)
+		assert.Equal(t, testMessageID, result.ID)
+
+		entry, err := cache.Get(context.Background(), testMessagePath)
+		require.NoError(t, err)
+		assert.Equal(t, `"v2"`, entry.ETag)
+	})
+
+	t.Run("not found removes the entry", func(t *testing.T) {
+		cache := NewLRUCache(0)
+		require.NoError(t, cache.Set(context.Background(), testMessagePath, CacheEntry{Body: []byte(`{}`), ETag: `"v1"`}))
+
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(mock.Anything).
+			Return(newTestResponse(http.StatusNotFound, readFile(t, "testdata/error_response.json")), nil).Once()
+
+		c := NewClient("test-api-key", nil, WithCache(cache))
+		c.doer = mDoer
+		_, _, err := c.GetMessage(context.Background(), testMessageID)
+		require.Error(t, err)
+
+		_, err = cache.Get(context.Background(), testMessagePath)
+		assert.ErrorIs(t, err, ErrCacheMiss)
+	})
+}
+
+func TestClient_GetMessageSourceCode_cache(t *testing.T) {
+	mDoer := newMockDoer(t)
+	mDoer.EXPECT().Do(isRequest(http.MethodGet, testMessagePath+"/source")).
+		Return(newTestResponse(http.StatusOK, []byte(`{"data":"source"}`)), nil).Once()
+
+	c := NewClient("test-api-key", nil, WithCache(NewLRUCache(0)))
+	c.doer = mDoer
+	for i := 0; i < 2; i++ {
+		result, _, err := c.GetMessageSourceCode(context.Background(), testMessageID)
+		require.NoError(t, err)
+		assert.Equal(t, "source", result.Data)
+	}
+}
+
+func TestClient_cacheInvalidation(t *testing.T) {
+	t.Run("DeleteMessage", func(t *testing.T) {
+		cache := NewLRUCache(0)
+		require.NoError(t, cache.Set(context.Background(), testMessagePath, CacheEntry{Body: []byte(`{}`)}))
+		require.NoError(t, cache.Set(context.Background(), testMessagePath+"/source", CacheEntry{Body: []byte(`{}`)}))
+
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(isRequest(http.MethodDelete, testMessagePath)).Return(newTestResponse(http.StatusOK, nil), nil)
+
+		c := NewClient("test-api-key", nil, WithCache(cache))
+		c.doer = mDoer
+		_, err := c.DeleteMessage(context.Background(), testMessageID)
+		require.NoError(t, err)
+		assert.Zero(t, cache.Len())
+	})
+
+	t.Run("DeleteEmail", func(t *testing.T) {
+		cache := NewLRUCache(0)
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(isRequest(http.MethodGet, "/v1/emails/user@example.com/messages")).
+			Return(newTestResponse(http.StatusOK, readFile(t, "testdata/list_email_messages.json")), nil)
+		mDoer.EXPECT().Do(isRequest(http.MethodGet, testMessagePath)).
+			Return(newTestResponse(http.StatusOK, readFile(t, "testdata/get_message.json")), nil)
+		mDoer.EXPECT().Do(isRequest(http.MethodDelete, "/v1/emails/user@example.com")).
+			Return(newTestResponse(http.StatusOK, nil), nil)
+
+		c := NewClient("test-api-key", nil, WithCache(cache))
+		c.doer = mDoer
+		_, _, err := c.ListEmailMessages(context.Background(), "user@example.com")
+		require.NoError(t, err)
+		_, _, err = c.GetMessage(context.Background(), testMessageID)
+		require.NoError(t, err)
+		require.Equal(t, 1, cache.Len())
+
+		_, err = c.DeleteEmail(context.Background(), "user@example.com")
+		require.NoError(t, err)
+		assert.Zero(t, cache.Len())
+	})
+}
+
+func TestClient_cacheInvalidation_deleteEmail(t *testing.T) {
+	// messageResponse returns the message with the ID of the requested path.
+	messageResponse := func(to string) func(*http.Request) (*http.Response, error) {
+		return func(req *http.Request) (*http.Response, error) {
+			id := path.Base(req.URL.Path)
+			return newTestResponse(http.StatusOK, []byte(`{"id":"`+id+`","to":"`+to+`"}`)), nil
+		}
+	}
+
+	t.Run("To header in another form", func(t *testing.T) {
+		cache := NewLRUCache(0)
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(isRequest(http.MethodGet, testMessagePath)).
+			RunAndReturn(messageResponse(`Test User <Test@Example.COM>`)).Once()
+		mDoer.EXPECT().Do(isRequest(http.MethodDelete, "/v1/emails/test@example.com")).
+			Return(newTestResponse(http.StatusOK, nil), nil).Once()
+
+		c := NewClient("test-api-key", nil, WithCache(cache))
+		c.doer = mDoer
+		_, _, err := c.GetMessage(context.Background(), testMessageID)
+		require.NoError(t, err)
+		require.Equal(t, 1, cache.Len())
+
+		_, err = c.DeleteEmail(context.Background(), "test@example.com")
+		require.NoError(t, err)
+		assert.Zero(t, cache.Len())
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		cache := NewLRUCache(0)
+		mDoer := newMockDoer(t)
+		c, now := newExpiryTestClient(t, mDoer, WithCache(cache))
+		mDoer.EXPECT().Do(isRequest(http.MethodGet, testMessagePath)).
+			RunAndReturn(messageResponse("test@example.com")).Once()
+		_, _, err := c.GetMessage(context.Background(), testMessageID)
+		require.NoError(t, err)
+		require.Equal(t, 1, cache.Len())
+
+		*now = now.Add(2 * time.Hour)
+		_, err = c.DeleteEmail(context.Background(), "test@example.com")
+		assert.ErrorIs(t, err, ErrEmailExpired)
+		assert.Zero(t, cache.Len())
+		assert.Zero(t, c.cached.len())
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		cache := NewLRUCache(0)
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(isRequest(http.MethodGet, testMessagePath)).
+			RunAndReturn(messageResponse("test@example.com")).Once()
+		mDoer.EXPECT().Do(isRequest(http.MethodDelete, "/v1/emails/test@example.com")).
+			Return(newTestResponse(http.StatusNotFound, readFile(t, "testdata/error_response.json")), nil).Once()
+
+		c := NewClient("test-api-key", nil, WithCache(cache))
+		c.doer = mDoer
+		_, _, err := c.GetMessage(context.Background(), testMessageID)
+		require.NoError(t, err)
+
+		_, err = c.DeleteEmail(context.Background(), "test@example.com")
+		assert.True(t, isNotFound(err))
+		assert.Zero(t, cache.Len())
+	})
+
+	t.Run("eviction", func(t *testing.T) {
+		cache := NewLRUCache(2)
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(mock.Anything).RunAndReturn(messageResponse("test@example.com")).Times(3)
+
+		c := NewClient("test-api-key", nil, WithCache(cache))
+		c.doer = mDoer
+		for _, id := range []string{"1", "2", "3"} {
+			_, _, err := c.GetMessage(context.Background(), id)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 2, cache.Len())
+		assert.Equal(t, 2, c.cached.len())
+	})
+}
+
+func TestCacheIndex(t *testing.T) {
+	now := time.Now()
+
+	t.Run("limit", func(t *testing.T) {
+		i := newCacheIndex(2)
+		assert.Empty(t, i.add("a@example.com", time.Time{}, now, "1", "2"))
+		assert.Equal(t, []string{"1"}, i.add("b@example.com", time.Time{}, now, "3"))
+		assert.Equal(t, 2, i.len())
+		assert.Equal(t, []string{"2"}, i.remove("A@Example.com"))
+	})
+
+	t.Run("expired addresses are dropped", func(t *testing.T) {
+		i := newCacheIndex(10)
+		i.add("a@example.com", now.Add(time.Minute), now, "1")
+		assert.Empty(t, i.add("b@example.com", time.Time{}, now, "2"))
+		assert.Equal(t, []string{"1"}, i.add("b@example.com", time.Time{}, now.Add(time.Hour), "3"))
+		assert.Equal(t, 2, i.len())
+	})
+
+	t.Run("message of several addresses", func(t *testing.T) {
+		i := newCacheIndex(10)
+		i.add("a@example.com", time.Time{}, now, "1")
+		i.add("b@example.com", time.Time{}, now, "1")
+		i.forget("1")
+		assert.Zero(t, i.len())
+		assert.Empty(t, i.remove("a@example.com"))
+	})
+}
+
+func TestIndexKey(t *testing.T) {
+	for _, email := range []string{"test@example.com", "Test@Example.COM", "Test User <test@example.com>"} {
+		assert.Equal(t, "test@example.com", indexKey(email), email)
+	}
+}
+
+func TestClient_cacheKey(t *testing.T) {
+	t.Run("ID containing a slash", func(t *testing.T) {
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(isRequest(http.MethodGet, "/v1/messages/abc/source")).
+			RunAndReturn(func(req *http.Request) (*http.Response, error) {
+				if req.URL.EscapedPath() == "/v1/messages/abc/source" {
+					return newTestResponse(http.StatusOK, []byte(`{"data":"source"}`)), nil
+				}
+				return newTestResponse(http.StatusOK, readFile(t, "testdata/get_message.json")), nil
+			}).Twice()
+
+		c := NewClient("test-api-key", nil, WithCache(NewLRUCache(0)))
+		c.doer = mDoer
+		_, _, err := c.GetMessageSourceCode(context.Background(), "abc")
+		require.NoError(t, err)
+		message, resp, err := c.GetMessage(context.Background(), "abc/source")
+		require.NoError(t, err)
+		assert.False(t, resp.Cached)
+		assert.Equal(t, testMessageID, message.ID)
+	})
+
+	t.Run("ID containing a percent sign", func(t *testing.T) {
+		cache := NewLRUCache(0)
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(isRequest(http.MethodGet, "/v1/messages/a%b")).
+			Return(newTestResponse(http.StatusOK, readFile(t, "testdata/get_message.json")), nil).Once()
+		mDoer.EXPECT().Do(isRequest(http.MethodDelete, "/v1/messages/a%b")).
+			Return(newTestResponse(http.StatusOK, nil), nil).Once()
+
+		c := NewClient("test-api-key", nil, WithCache(cache))
+		c.doer = mDoer
+		_, _, err := c.GetMessage(context.Background(), "a%b")
+		require.NoError(t, err)
+		require.Equal(t, 1, cache.Len())
+
+		_, err = c.DeleteMessage(context.Background(), "a%b")
+		require.NoError(t, err)
+		assert.Zero(t, cache.Len())
+	})
+}
+
+func TestLRUCache(t *testing.T) {
+	ctx := context.Background()
+	c := NewLRUCache(2)
+
+	_, err := c.Get(ctx, "a")
+	assert.ErrorIs(t, err, ErrCacheMiss)
+
+	require.NoError(t, c.Set(ctx, "a", CacheEntry{Body: []byte("a")}))
+	require.NoError(t, c.Set(ctx, "b", CacheEntry{Body: []byte("b")}))
+	_, err = c.Get(ctx, "a")
+	require.NoError(t, err)
+	require.NoError(t, c.Set(ctx, "c", CacheEntry{Body: []byte("c")}))
+
+	// b was the least recently used entry.
+	_, err = c.Get(ctx, "b")
+	assert.ErrorIs(t, err, ErrCacheMiss)
+	assert.Equal(t, 2, c.Len())
+
+	entry, err := c.Get(ctx, "a")
+	require.NoError(t, err)
+	entry.Body[0] = 'x'
+	entry, err = c.Get(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, []byte("a"), entry.Body)
+
+	require.NoError(t, c.Delete(ctx, "a"))
+	require.NoError(t, c.Delete(ctx, "a"))
+	assert.Equal(t, 1, c.Len())
+}
+
+func TestDiskCache(t *testing.T) {
+	ctx := context.Background()
+	dir := filepath.Join(t.TempDir(), "cache")
+	c := NewDiskCache(dir)
+
+	_, err := c.Get(ctx, testMessagePath)
+	assert.ErrorIs(t, err, ErrCacheMiss)
+
+	entry := CacheEntry{Body: []byte(`{"id":"1"}`), ETag: `"v1"`}
+	require.NoError(t, c.Set(ctx, testMessagePath, entry))
+
+	got, err := NewDiskCache(dir).Get(ctx, testMessagePath)
+	require.NoError(t, err)
+	assert.Equal(t, entry, got)
+
+	require.NoError(t, c.Delete(ctx, testMessagePath))
+	require.NoError(t, c.Delete(ctx, testMessagePath))
+	_, err = c.Get(ctx, testMessagePath)
+	assert.ErrorIs(t, err, ErrCacheMiss)
+
+	t.Run("corrupted entry", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(c.path("bad"), []byte("{"), 0o600))
+		_, err := c.Get(ctx, "bad")
+		assert.ErrorContains(t, err, "tempmail: decode cache entry bad")
+	})
+}
@@ -0,0 +1,95 @@
+package tempmail
+
+import (
+	"net/http"
+	"time"
+)
+
+const headerIdempotencyKey = "Idempotency-Key"
+
+// CallOption configures the calls made through a Client returned by Client.With.
+type CallOption func(*callOptions)
+
+// callOptions are the per-call settings of a Client.
+type callOptions struct {
+	timeout        time.Duration
+	header         http.Header
+	retry          *RetryPolicy
+	noCache        bool
+	idempotencyKey string
+}
+
+// With returns a copy of the Client whose calls use the options, for example a longer
+// timeout for a single DownloadAttachment call:
+//
+//	data, _, err := client.With(tempmail.WithTimeout(time.Minute)).DownloadAttachment(ctx, id)
+//
+// The copy shares everything else with the Client, such as its cache, circuit breaker and
+// API keys. Options add to those of the Client it is called on.
+func (c *Client) With(opts ...CallOption) *Client {
+	derived := *c
+	derived.call.header = c.call.header.Clone()
+	for _, opt := range opts {
+		opt(&derived.call)
+	}
+	return &derived
+}
+
+// WithTimeout limits the time of each call, including retries and reading the response.
+// It replaces the default timeout of the operation, see Timeouts.
+// A call that exceeds it returns a *TimeoutError.
+func WithTimeout(d time.Duration) CallOption {
+	return func(o *callOptions) {
+		o.timeout = d
+	}
+}
+
+// WithHeader adds a header to each request, for example a trace ID.
+// The API key header can't be overridden.
+func WithHeader(key, value string) CallOption {
+	return func(o *callOptions) {
+		if o.header == nil {
+			o.header = make(http.Header)
+		}
+		o.header.Add(key, value)
+	}
+}
+
+// WithRetryPolicy retries failed calls according to the policy.
+// Use a zero RetryPolicy to disable retries.
+func WithRetryPolicy(p RetryPolicy) CallOption {
+	return func(o *callOptions) {
+		o.retry = &p
+	}
+}
+
+// WithoutCache bypasses the Cache when reading, so the response is fetched from the API.
+// The fresh response still replaces the cached entry.
+func WithoutCache() CallOption {
+	return func(o *callOptions) {
+		o.noCache = true
+	}
+}
+
+// WithIdempotencyKey sends the key in the Idempotency-Key header of each request.
+// The header is not part of the documented API, so check that the API supports it
+// before relying on it to detect repeated requests.
+// It doesn't make POST requests retryable.
+func WithIdempotencyKey(key string) CallOption {
+	return func(o *callOptions) {
+		o.idempotencyKey = key
+	}
+}
+
+// setHeaders sets the per-call headers of the request.
+func (o callOptions) setHeaders(req *http.Request) {
+	for key, values := range o.header {
+		if http.CanonicalHeaderKey(key) == http.CanonicalHeaderKey(headerAPIKey) {
+			continue
+		}
+		req.Header[http.CanonicalHeaderKey(key)] = append([]string(nil), values...)
+	}
+	if o.idempotencyKey != "" {
+		req.Header.Set(headerIdempotencyKey, o.idempotencyKey)
+	}
+}
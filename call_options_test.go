@@ -0,0 +1,72 @@
+package tempmail
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_With(t *testing.T) {
+	t.Run("headers", func(t *testing.T) {
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(mock.MatchedBy(func(req *http.Request) bool {
+			return req.Header.Get("X-Trace-Id") == "trace-1" &&
+				req.Header.Get(headerIdempotencyKey) == "create-1" &&
+				req.Header.Get(headerAPIKey) == "API_KEY"
+		})).Return(newTestResponse(http.StatusOK, readFile(t, "testdata/create_email.json")), nil)
+
+		c := newClient()
+		c.doer = mDoer
+		_, _, err := c.With(
+			WithHeader("X-Trace-Id", "trace-1"),
+			WithHeader(headerAPIKey, "other"),
+			WithIdempotencyKey("create-1"),
+		).CreateEmail(context.Background(), CreateEmailOptions{})
+		require.NoError(t, err)
+	})
+
+	t.Run("derived clients don't change the parent", func(t *testing.T) {
+		c := newClient()
+		traced := c.With(WithHeader("X-Trace-Id", "trace-1"))
+		both := traced.With(WithHeader("X-Team", "qa"), WithTimeout(time.Second))
+
+		assert.Empty(t, c.call.header)
+		assert.Equal(t, http.Header{"X-Trace-Id": {"trace-1"}}, traced.call.header)
+		assert.Zero(t, traced.call.timeout)
+		assert.Equal(t, http.Header{"X-Trace-Id": {"trace-1"}, "X-Team": {"qa"}}, both.call.header)
+		assert.Same(t, c.known, both.known)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		c := newClient()
+		c.doer = blockingDoer(t)
+		_, _, err := c.With(WithTimeout(10*time.Millisecond)).DownloadAttachment(context.Background(), "attachment")
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("without cache", func(t *testing.T) {
+		cache := NewLRUCache(0)
+		require.NoError(t, cache.Set(context.Background(), testMessagePath, CacheEntry{Body: []byte(`{"id":"stale"}`)}))
+
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(mock.Anything).Return(newTestResponse(http.StatusOK, readFile(t, "testdata/get_message.json")), nil).Once()
+
+		c := NewClient("API_KEY", nil, WithCache(cache))
+		c.doer = mDoer
+		message, resp, err := c.With(WithoutCache()).GetMessage(context.Background(), testMessageID)
+		require.NoError(t, err)
+		assert.False(t, resp.Cached)
+		assert.Equal(t, testMessageID, message.ID)
+
+		// The fresh response replaces the cached entry.
+		message, resp, err = c.GetMessage(context.Background(), testMessageID)
+		require.NoError(t, err)
+		assert.True(t, resp.Cached)
+		assert.Equal(t, testMessageID, message.ID)
+	})
+}
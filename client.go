@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
+	"time"
 )
 
 type doer interface {
@@ -25,6 +27,97 @@ type Client struct {
 	doer doer
 	// apiKey is an API key for the Temp Mail API.
 	apiKey string
+	// keys chooses one of several API keys per request. Optional.
+	keys *keyPool
+	// credentials provides the API key instead of apiKey. Optional.
+	credentials *credentialCache
+	// tracer receives a span for every API call. Optional.
+	tracer Tracer
+	// metrics receives counters, latencies and rate limit gauges. Optional.
+	metrics Metrics
+	// known tracks the expiry of the email addresses created by the client.
+	known *registry
+	// store persists the created email addresses. Optional.
+	store Store
+	// cache stores the responses of immutable message resources. Optional.
+	cache Cache
+	// cached tracks the cached messages of every email address.
+	cached *cacheIndex
+	// breaker stops calls to the API after repeated failures. Optional.
+	breaker *circuitBreaker
+	// limits are the maximum response sizes of the operation classes.
+	limits ResponseLimits
+	// strict fails calls whose response has unknown fields.
+	strict bool
+	// onUnknownFields receives the unknown fields of responses. Optional.
+	onUnknownFields func(UnknownFields)
+	// hedge sends hedged requests for slow GET requests. Optional.
+	hedge *hedger
+	// limit delays bulk operations while the rate limit is exhausted.
+	limit *rateLimiter
+	// timeouts are the default timeouts of the operation classes.
+	timeouts Timeouts
+	// call holds the per-call options set by With.
+	call callOptions
+	// now returns the current time.
+	now func() time.Time
+}
+
+// ClientOption configures optional Client behavior.
+type ClientOption func(*Client)
+
+// Operation identifies an API method of the Client.
+type Operation string
+
+const (
+	OperationCreateEmail          Operation = "CreateEmail"
+	OperationDeleteEmail          Operation = "DeleteEmail"
+	OperationDeleteMessage        Operation = "DeleteMessage"
+	OperationDownloadAttachment   Operation = "DownloadAttachment"
+	OperationGetMessage           Operation = "GetMessage"
+	OperationGetMessageSourceCode Operation = "GetMessageSourceCode"
+	OperationListDomains          Operation = "ListDomains"
+	OperationListEmailMessages    Operation = "ListEmailMessages"
+	OperationRateLimit            Operation = "RateLimit"
+	// OperationRequest is a request built with NewRequest and sent with Do.
+	OperationRequest Operation = "Request"
+)
+
+// operationClass groups the operations that share default timeouts and response limits.
+type operationClass int
+
+const (
+	classOther operationClass = iota
+	classMetadata
+	classMessage
+	classAttachment
+	classMutation
+)
+
+// class returns the class of the operation.
+func (op Operation) class() operationClass {
+	switch op {
+	case OperationListDomains, OperationRateLimit:
+		return classMetadata
+	case OperationListEmailMessages, OperationGetMessage, OperationGetMessageSourceCode, OperationRequest:
+		// Endpoints without a method get the most generous limits of the JSON endpoints.
+		return classMessage
+	case OperationDownloadAttachment:
+		return classAttachment
+	case OperationCreateEmail, OperationDeleteEmail, OperationDeleteMessage:
+		return classMutation
+	default:
+		return classOther
+	}
+}
+
+// operationKey is the context key under which the request Operation is stored.
+type operationKey struct{}
+
+// operationFromContext returns the Operation stored by newRequest.
+func operationFromContext(ctx context.Context) Operation {
+	op, _ := ctx.Value(operationKey{}).(Operation)
+	return op
 }
 
 const (
@@ -40,18 +133,27 @@ const (
 )
 
 // NewClient creates ready to use Client.
-func NewClient(apiKey string, client *http.Client) *Client {
+func NewClient(apiKey string, client *http.Client, opts ...ClientOption) *Client {
 	if client == nil {
 		client = http.DefaultClient
 	}
-	return &Client{
-		doer:   client,
-		apiKey: apiKey,
+	c := &Client{
+		doer:     client,
+		apiKey:   apiKey,
+		known:    newRegistry(),
+		limit:    newRateLimiter(time.Now),
+		timeouts: DefaultTimeouts,
+		limits:   DefaultResponseLimits,
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	return c
 }
 
-// newRequest creates a new HTTP request.
-func (c *Client) newRequest(ctx context.Context, method, path string, data interface{}) (*http.Request, error) {
+// newRequest creates a new HTTP request for the given operation.
+func (c *Client) newRequest(ctx context.Context, op Operation, method, path string, data interface{}) (*http.Request, error) {
 	var body io.Reader
 	if data != nil {
 		b, err := json.Marshal(data)
@@ -60,55 +162,156 @@ func (c *Client) newRequest(ctx context.Context, method, path string, data inter
 		}
 		body = bytes.NewReader(b)
 	}
+	if ctx != nil {
+		ctx = context.WithValue(ctx, operationKey{}, op)
+	}
 	req, err := http.NewRequestWithContext(ctx, method, baseURL+path, body)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set(headerAPIKey, c.apiKey)
+	key, err := c.apiKeyFor(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(headerAPIKey, key)
 	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(headerAcceptEncoding, "gzip")
+	c.call.setHeaders(req)
 	return req, nil
 }
 
 // do sends an HTTP request and decodes the response.
 func (c *Client) do(req *http.Request, v interface{}) (*Response, error) {
-	r, err := c.rawDo(req)
-	if err != nil {
-		return nil, err
+	return c.send(req, func(r *Response) error {
+		if v == nil {
+			return nil
+		}
+		return c.decode(operationFromContext(req.Context()), r.Body, v)
+	})
+}
+
+// send sends an HTTP request, checks the response for errors and passes
+// the successful response to handle before closing its body.
+// It reports the call to the configured Tracer and Metrics.
+func (c *Client) send(req *http.Request, handle func(r *Response) error) (*Response, error) {
+	op := operationFromContext(req.Context())
+	start := time.Now()
+
+	parent := req.Context()
+	timeout := c.callTimeout(parent, op)
+	if timeout > 0 {
+		ctx, cancel := context.WithTimeout(parent, timeout)
+		defer cancel()
+		req = req.WithContext(ctx)
 	}
-	defer r.Body.Close()
 
-	if err := c.checkResponse(r); err != nil {
+	var span Span
+	if c.tracer != nil {
+		var ctx context.Context
+		ctx, span = c.tracer.StartCall(req.Context(), op)
+		req = req.WithContext(ctx)
+	}
+
+	r, retries, err := c.roundTripWithRetries(req, handle)
+	if timeout > 0 {
+		err = timeoutError(parent, req.Context(), op, timeout, err)
+	}
+
+	info := CallInfo{
+		Operation: op,
+		Method:    req.Method,
+		Retries:   retries,
+		Duration:  time.Since(start),
+		Err:       err,
+	}
+	if r != nil {
+		info.StatusCode = r.StatusCode
+		info.Rate = r.Rate
+		// With several API keys, the rate limit of one key doesn't apply to the others.
+		if c.keys == nil {
+			c.limit.update(r.Rate)
+		}
+	}
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		info.ErrorCode = httpErr.ErrorDetails.Code
+	}
+	c.observe(req.Context(), span, info)
+
+	if err != nil {
 		return nil, err
 	}
+	return r, nil
+}
 
-	if v != nil {
-		if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+// roundTrip sends the request through the circuit breaker, if any, and handles the response.
+func (c *Client) roundTrip(req *http.Request, handle func(r *Response) error) (*Response, error) {
+	if c.breaker == nil {
+		r, err := c.rawDoWithCredentials(req)
+		if err != nil {
 			return nil, err
 		}
+		return r, c.handleResponse(operationFromContext(req.Context()), r, handle)
 	}
 
-	return r, nil
+	done, err := c.breaker.allow()
+	if err != nil {
+		return nil, err
+	}
+	r, err := c.rawDoWithCredentials(req)
+	if err != nil {
+		done(isBreakerFailure(err, 0))
+		return nil, err
+	}
+	done(isBreakerFailure(nil, r.StatusCode))
+	return r, c.handleResponse(operationFromContext(req.Context()), r, handle)
+}
+
+// handleResponse checks the response of the operation and passes it to handle.
+// It always closes the response body.
+func (c *Client) handleResponse(op Operation, r *Response, handle func(r *Response) error) error {
+	defer r.Body.Close()
+
+	if err := c.checkResponse(op, r); err != nil {
+		return err
+	}
+	if handle != nil {
+		return handle(r)
+	}
+	return nil
 }
 
 // rawDo sends an HTTP request and returns the response.
 // It does not decode the response body nor check the status code.
 // Caller is responsible for closing the response body.
 func (c *Client) rawDo(req *http.Request) (*Response, error) {
-	r, err := c.doer.Do(req)
+	var r *http.Response
+	var err error
+	if c.hedge != nil && hedgeable(req) {
+		r, err = c.hedge.do(c.doer, req)
+	} else {
+		r, err = c.doer.Do(req)
+	}
 	if err != nil {
 		return nil, err
 	}
+	c.limitBody(req, r)
 
 	return newResponse(r), nil
 }
 
-// checkResponse checks the response for errors.
-func (c *Client) checkResponse(r *Response) error {
-	if r.StatusCode < 200 || r.StatusCode >= 300 {
+// checkResponse checks the response of the operation for errors.
+// 304 Not Modified is not an error, since it is only sent for conditional requests.
+func (c *Client) checkResponse(op Operation, r *Response) error {
+	if (r.StatusCode < 200 || r.StatusCode >= 300) && r.StatusCode != http.StatusNotModified {
 		httpErr := HTTPError{
 			Response: r.Response,
 		}
-		if err := json.NewDecoder(r.Body).Decode(&httpErr); err != nil {
+		if err := c.decode(op, r.Body, &httpErr); err != nil {
+			var unknown *UnknownFieldsError
+			if errors.As(err, &unknown) {
+				return errors.Join(&httpErr, err)
+			}
 			return err
 		}
 		return &httpErr
@@ -36,7 +36,7 @@ func newClient() *Client {
 func TestNewRequest(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		c := newClient()
-		req, err := c.newRequest(context.Background(), http.MethodGet, "/v1/emails", nil)
+		req, err := c.newRequest(context.Background(), "", http.MethodGet, "/v1/emails", nil)
 		require.NoError(t, err)
 		require.NotNil(t, req)
 		assert.Equal(t, http.MethodGet, req.Method)
@@ -47,7 +47,7 @@ func TestNewRequest(t *testing.T) {
 
 	t.Run("custom body", func(t *testing.T) {
 		c := newClient()
-		req, err := c.newRequest(context.Background(), http.MethodPost, "/v1/emails", createEmailRequest{
+		req, err := c.newRequest(context.Background(), "", http.MethodPost, "/v1/emails", createEmailRequest{
 			Domain: "example.com",
 		})
 		require.NoError(t, err)
@@ -62,7 +62,7 @@ func TestNewRequest(t *testing.T) {
 		c := newClient()
 		// Create a value that cannot be marshaled to JSON
 		invalidData := make(chan int)
-		req, err := c.newRequest(context.Background(), http.MethodPost, "/v1/emails", invalidData)
+		req, err := c.newRequest(context.Background(), "", http.MethodPost, "/v1/emails", invalidData)
 		assert.Error(t, err)
 		assert.Nil(t, req)
 	})
@@ -83,6 +83,13 @@ func newTestResponse(statusCode int, body []byte) *http.Response {
 	}
 }
 
+// isRequest matches a request with the given method and URL path.
+func isRequest(method, path string) interface{} {
+	return mock.MatchedBy(func(req *http.Request) bool {
+		return req.Method == method && req.URL.Path == path
+	})
+}
+
 func TestClient_do(t *testing.T) {
 	t.Run("success with nil v", func(t *testing.T) {
 		mDoer := newMockDoer(t)
@@ -90,7 +97,7 @@ func TestClient_do(t *testing.T) {
 
 		c := newClient()
 		c.doer = mDoer
-		req, err := c.newRequest(context.Background(), http.MethodGet, "/test", nil)
+		req, err := c.newRequest(context.Background(), "", http.MethodGet, "/test", nil)
 		require.NoError(t, err)
 		resp, err := c.do(req, nil)
 		require.NoError(t, err)
@@ -103,7 +110,7 @@ func TestClient_do(t *testing.T) {
 
 		c := newClient()
 		c.doer = mDoer
-		req, err := c.newRequest(context.Background(), http.MethodGet, "/test", nil)
+		req, err := c.newRequest(context.Background(), "", http.MethodGet, "/test", nil)
 		require.NoError(t, err)
 		_, err = c.do(req, nil)
 		assert.EqualError(t, err, assert.AnError.Error())
@@ -115,7 +122,7 @@ func TestClient_do(t *testing.T) {
 
 		c := newClient()
 		c.doer = mDoer
-		req, err := c.newRequest(context.Background(), http.MethodGet, "/test", nil)
+		req, err := c.newRequest(context.Background(), "", http.MethodGet, "/test", nil)
 		require.NoError(t, err)
 		_, err = c.do(req, nil)
 		require.Error(t, err)
@@ -138,7 +145,7 @@ func TestClient_do(t *testing.T) {
 
 		c := newClient()
 		c.doer = mDoer
-		req, err := c.newRequest(context.Background(), http.MethodGet, "/test", nil)
+		req, err := c.newRequest(context.Background(), "", http.MethodGet, "/test", nil)
 		require.NoError(t, err)
 		var result map[string]interface{}
 		_, err = c.do(req, &result)
@@ -151,14 +158,14 @@ func TestClient_checkResponse(t *testing.T) {
 	t.Run("success response", func(t *testing.T) {
 		c := newClient()
 		resp := newResponse(newTestResponse(http.StatusOK, []byte{}))
-		err := c.checkResponse(resp)
+		err := c.checkResponse(OperationGetMessage, resp)
 		require.NoError(t, err)
 	})
 
 	t.Run("error response", func(t *testing.T) {
 		c := newClient()
 		resp := newResponse(newTestResponse(http.StatusBadRequest, readFile(t, "testdata/error_response.json")))
-		err := c.checkResponse(resp)
+		err := c.checkResponse(OperationGetMessage, resp)
 		require.Error(t, err)
 		var httpErr *HTTPError
 		require.ErrorAs(t, err, &httpErr)
@@ -170,7 +177,7 @@ func TestClient_checkResponse(t *testing.T) {
 	t.Run("error response with invalid JSON", func(t *testing.T) {
 		c := newClient()
 		resp := newResponse(newTestResponse(http.StatusBadGateway, []byte("invalid json")))
-		err := c.checkResponse(resp)
+		err := c.checkResponse(OperationGetMessage, resp)
 		require.Error(t, err)
 		assert.Errorf(t, err, "invalid character 'i' looking for beginning of value")
 	})
@@ -184,7 +191,7 @@ func TestClient_rawDo(t *testing.T) {
 
 		c := newClient()
 		c.doer = mDoer
-		req, err := c.newRequest(context.Background(), http.MethodGet, "/test", nil)
+		req, err := c.newRequest(context.Background(), "", http.MethodGet, "/test", nil)
 		require.NoError(t, err)
 		resp, err := c.rawDo(req)
 		require.NoError(t, err)
@@ -197,7 +204,7 @@ func TestClient_rawDo(t *testing.T) {
 
 		c := newClient()
 		c.doer = mDoer
-		req, err := c.newRequest(context.Background(), http.MethodGet, "/test", nil)
+		req, err := c.newRequest(context.Background(), "", http.MethodGet, "/test", nil)
 		require.NoError(t, err)
 		_, err = c.rawDo(req)
 		assert.EqualError(t, err, assert.AnError.Error())
@@ -6,24 +6,18 @@ import (
 	"time"
 )
 
-const (
-	// DomainTypePublic is a public domain.
-	DomainTypePublic = "public"
-	// DomainTypeCustom is user-provided domain.
-	DomainTypeCustom = "custom"
-	// DomainTypePremium is a premium domain.
-	DomainTypePremium = "premium"
-)
-
 // CreateEmailOptions represents the options to create an email.
 type CreateEmailOptions struct {
 	// Email is the email address to create. If not provided, a random email address will be generated
 	Email string
 	// DomainType is the type of domain to use for the email address.
 	// Possible values are: "public", "custom", "premium"
-	DomainType string
+	DomainType DomainType
 	// Domain is the domain to use for the email address.
 	Domain string
+	// Labels are saved with the email address to the Store configured with WithStore.
+	// They are not sent to the API.
+	Labels map[string]string
 }
 
 // createEmailRequest represents the request to create an email
@@ -32,7 +26,7 @@ type createEmailRequest struct {
 	Email string `json:"email,omitempty"`
 	// DomainType is the type of domain to use for the email address.
 	// Possible values are: "public", "custom", "premium"
-	DomainType string `json:"domain_type,omitempty"`
+	DomainType DomainType `json:"domain_type,omitempty"`
 	// Domain is the domain to use for the email address.
 	Domain string `json:"domain,omitempty"`
 }
@@ -50,13 +44,25 @@ type CreateEmailResponse struct {
 	Email string
 	// TTL is the time to live of the email address in seconds.
 	TTL time.Duration
+	// ExpiresAt is the time at which the email address expires.
+	// It is calculated from the TTL and the time the request was sent.
+	ExpiresAt time.Time
 }
 
 // CreateEmail creates an email address.
 // It returns the email address and the time to live of the email address.
 // You should use this method before getting messages for the email address.
+// If saving to the Store fails, the created email address is returned along with the error.
 func (c *Client) CreateEmail(ctx context.Context, options CreateEmailOptions) (CreateEmailResponse, *Response, error) {
-	req, err := c.newRequest(ctx, http.MethodPost, "/v1/emails", createEmailRequest(options))
+	if options.DomainType != "" && !options.DomainType.Valid() {
+		return CreateEmailResponse{}, nil, &ValidationError{Field: "domainType", Value: string(options.DomainType), Reason: "unknown domain type"}
+	}
+	start := c.now()
+	req, err := c.newRequest(ctx, OperationCreateEmail, http.MethodPost, "/v1/emails", createEmailRequest{
+		Email:      options.Email,
+		DomainType: options.DomainType,
+		Domain:     options.Domain,
+	})
 	if err != nil {
 		return CreateEmailResponse{}, nil, err
 	}
@@ -67,8 +73,16 @@ func (c *Client) CreateEmail(ctx context.Context, options CreateEmailOptions) (C
 		return CreateEmailResponse{}, nil, err
 	}
 
-	return CreateEmailResponse{
+	result := CreateEmailResponse{
 		Email: resp.Email,
 		TTL:   time.Duration(resp.TTL) * time.Second,
-	}, r, nil
+	}
+	result.ExpiresAt = start.Add(result.TTL)
+	c.known.add(result.Email, result.ExpiresAt, start)
+	if err := c.storeCreated(ctx, result, options.Labels); err != nil {
+		// The email address exists anyway, so return it along with the error.
+		return result, r, err
+	}
+
+	return result, r, nil
 }
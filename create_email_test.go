@@ -69,17 +69,21 @@ func TestClient_CreateEmail(t *testing.T) {
 		mDoer := newMockDoer(t)
 		mDoer.EXPECT().Do(mock.Anything).Return(newTestResponse(http.StatusOK, readFile(t, "testdata/create_email.json")), nil)
 
+		now := time.Date(2025, 1, 31, 22, 0, 0, 0, time.UTC)
 		c := newClient()
 		c.doer = mDoer
+		c.now = func() time.Time { return now }
 		result, resp, err := c.CreateEmail(context.Background(), CreateEmailOptions{})
 		require.NoError(t, err)
 		assert.Equal(t, 200, resp.StatusCode)
 
 		expected := CreateEmailResponse{
-			Email: "test@example.com",
-			TTL:   3600 * time.Second,
+			Email:     "test@example.com",
+			TTL:       3600 * time.Second,
+			ExpiresAt: now.Add(time.Hour),
 		}
 		assert.Equal(t, expected, result)
+		assert.Equal(t, []KnownEmail{{Email: "test@example.com", ExpiresAt: now.Add(time.Hour)}}, c.Known())
 	})
 
 	t.Run("success with custom options", func(t *testing.T) {
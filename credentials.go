@@ -0,0 +1,188 @@
+package tempmail
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// DefaultCredentialCacheTTL is the time the Client reuses a key returned by a CredentialProvider.
+const DefaultCredentialCacheTTL = 5 * time.Minute
+
+// CredentialProvider returns the API key, for example from a secrets manager.
+// Implementations must be safe for concurrent use and must not include the key in errors.
+type CredentialProvider interface {
+	APIKey(ctx context.Context) (string, error)
+}
+
+// WithCredentials makes the Client get the API key from the provider instead of using
+// the key passed to NewClient. The key is cached for DefaultCredentialCacheTTL, or for
+// ttl if it is positive. If the API rejects the key with 401 Unauthorized, the Client gets
+// a fresh key from the provider and, if it changed, sends the request once more.
+// WithAPIKeys takes precedence over WithCredentials.
+func WithCredentials(provider CredentialProvider, ttl time.Duration) ClientOption {
+	return func(c *Client) {
+		if ttl <= 0 {
+			ttl = DefaultCredentialCacheTTL
+		}
+		c.credentials = &credentialCache{provider: provider, ttl: ttl, now: time.Now}
+	}
+}
+
+// String returns a description of the Client without its API key.
+func (c *Client) String() string {
+	return fmt.Sprintf("tempmail.Client{apiKey: %q}", maskKey(c.apiKey))
+}
+
+// GoString is like String, so the API key isn't printed with %#v either.
+func (c *Client) GoString() string {
+	return c.String()
+}
+
+// credentialCache caches the key of a CredentialProvider.
+type credentialCache struct {
+	provider CredentialProvider
+	ttl      time.Duration
+	now      func() time.Time
+
+	mu        sync.Mutex
+	key       string
+	fetchedAt time.Time
+}
+
+// get returns the cached key or gets a new one from the provider.
+func (c *credentialCache) get(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.key != "" && c.now().Sub(c.fetchedAt) < c.ttl {
+		return c.key, nil
+	}
+	return c.fetch(ctx)
+}
+
+// refresh gets a new key from the provider after the API rejected the given key.
+// Concurrent calls for the same rejected key get the provider only once.
+func (c *credentialCache) refresh(ctx context.Context, rejected string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.key != "" && c.key != rejected {
+		return c.key, nil
+	}
+	return c.fetch(ctx)
+}
+
+// fetch gets the key from the provider. c.mu must be held.
+func (c *credentialCache) fetch(ctx context.Context) (string, error) {
+	key, err := c.provider.APIKey(ctx)
+	if err != nil {
+		return "", fmt.Errorf("tempmail: get API key: %w", err)
+	}
+	if key == "" {
+		return "", errors.New("tempmail: get API key: empty key")
+	}
+	c.key, c.fetchedAt = key, c.now()
+	return key, nil
+}
+
+// rawDoWithCredentials sends the request and, if the API answers 401 Unauthorized,
+// refreshes the key of the CredentialProvider and sends the request once more.
+func (c *Client) rawDoWithCredentials(req *http.Request) (*Response, error) {
+	r, err := c.rawDoWithKeys(req)
+	if c.credentials == nil || c.keys != nil || err != nil || r.StatusCode != http.StatusUnauthorized {
+		return r, err
+	}
+
+	rejected := req.Header.Get(headerAPIKey)
+	key, err := c.credentials.refresh(req.Context(), rejected)
+	if err != nil || key == rejected {
+		return r, nil
+	}
+	retry, err := withAPIKey(req, key)
+	if err != nil {
+		return r, nil
+	}
+	r.Body.Close()
+	return c.rawDo(retry)
+}
+
+// StaticCredentials is a CredentialProvider returning a fixed key.
+type StaticCredentials struct {
+	key string
+}
+
+// NewStaticCredentials creates a StaticCredentials.
+func NewStaticCredentials(key string) *StaticCredentials {
+	return &StaticCredentials{key: key}
+}
+
+// APIKey implements CredentialProvider.
+func (s *StaticCredentials) APIKey(context.Context) (string, error) {
+	return s.key, nil
+}
+
+// String returns the masked key.
+func (s *StaticCredentials) String() string {
+	return maskKey(s.key)
+}
+
+// EnvCredentials is a CredentialProvider reading the key from the environment variable
+// it names, for example EnvCredentials("TEMP_MAIL_API_KEY").
+type EnvCredentials string
+
+// APIKey implements CredentialProvider.
+func (e EnvCredentials) APIKey(context.Context) (string, error) {
+	key, ok := os.LookupEnv(string(e))
+	if !ok || key == "" {
+		return "", fmt.Errorf("environment variable %s is not set", string(e))
+	}
+	return key, nil
+}
+
+// FileCredentials is a CredentialProvider reading the key from a file, for example
+// one mounted from a secrets manager. The file is read again when it changes.
+// Leading and trailing whitespace is ignored.
+type FileCredentials struct {
+	path string
+
+	mu      sync.Mutex
+	key     string
+	modTime time.Time
+	size    int64
+}
+
+// NewFileCredentials creates a FileCredentials reading the file at path.
+func NewFileCredentials(path string) *FileCredentials {
+	return &FileCredentials{path: path}
+}
+
+// APIKey implements CredentialProvider.
+func (f *FileCredentials) APIKey(context.Context) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return "", err
+	}
+	if f.key != "" && info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return f.key, nil
+	}
+
+	b, err := os.ReadFile(f.path)
+	if err != nil {
+		return "", err
+	}
+	key := string(bytes.TrimSpace(b))
+	if key == "" {
+		return "", fmt.Errorf("credentials file %s is empty", f.path)
+	}
+	f.key, f.modTime, f.size = key, info.ModTime(), info.Size()
+	return key, nil
+}
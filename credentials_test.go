@@ -0,0 +1,155 @@
+package tempmail
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// sequenceCredentials returns the keys in order, repeating the last one.
+type sequenceCredentials struct {
+	mu    sync.Mutex
+	keys  []string
+	calls int
+	err   error
+}
+
+func (s *sequenceCredentials) APIKey(context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	if s.err != nil {
+		return "", s.err
+	}
+	return s.keys[min(s.calls, len(s.keys))-1], nil
+}
+
+func TestClient_credentials(t *testing.T) {
+	domainsResponse := func(*http.Request) (*http.Response, error) {
+		return newTestResponse(http.StatusOK, []byte(`{"domains":[]}`)), nil
+	}
+
+	t.Run("cached", func(t *testing.T) {
+		now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		provider := &sequenceCredentials{keys: []string{"key-1", "key-2"}}
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(hasAPIKey("key-1")).RunAndReturn(domainsResponse).Twice()
+		mDoer.EXPECT().Do(hasAPIKey("key-2")).RunAndReturn(domainsResponse).Once()
+
+		c := NewClient("", nil, WithCredentials(provider, time.Minute))
+		c.credentials.now = func() time.Time { return now }
+		c.doer = mDoer
+
+		for i := 0; i < 2; i++ {
+			_, _, err := c.ListDomains(context.Background())
+			require.NoError(t, err)
+		}
+		now = now.Add(time.Minute)
+		_, _, err := c.ListDomains(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 2, provider.calls)
+	})
+
+	t.Run("refresh on 401", func(t *testing.T) {
+		provider := &sequenceCredentials{keys: []string{"old-key", "new-key"}}
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(hasAPIKey("old-key")).
+			Return(newTestResponse(http.StatusUnauthorized, readFile(t, "testdata/error_response.json")), nil).Once()
+		mDoer.EXPECT().Do(hasAPIKey("new-key")).RunAndReturn(domainsResponse).Twice()
+
+		c := NewClient("", nil, WithCredentials(provider, 0))
+		c.doer = mDoer
+		for i := 0; i < 2; i++ {
+			_, _, err := c.ListDomains(context.Background())
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 2, provider.calls)
+	})
+
+	t.Run("key unchanged after 401", func(t *testing.T) {
+		provider := &sequenceCredentials{keys: []string{"secret-api-key"}}
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(mock.Anything).
+			Return(newTestResponse(http.StatusUnauthorized, readFile(t, "testdata/error_response.json")), nil).Once()
+
+		c := NewClient("", nil, WithCredentials(provider, 0))
+		c.doer = mDoer
+		_, _, err := c.ListDomains(context.Background())
+		var httpErr *HTTPError
+		require.ErrorAs(t, err, &httpErr)
+		assert.NotContains(t, fmt.Sprintf("%+v", err), "secret-api-key")
+	})
+
+	t.Run("error from provider", func(t *testing.T) {
+		c := NewClient("", nil, WithCredentials(&sequenceCredentials{err: assert.AnError}, 0))
+		c.doer = newMockDoer(t)
+		_, _, err := c.ListDomains(context.Background())
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "tempmail: get API key: ")
+	})
+
+	t.Run("empty key", func(t *testing.T) {
+		c := NewClient("", nil, WithCredentials(NewStaticCredentials(""), 0))
+		c.doer = newMockDoer(t)
+		_, _, err := c.ListDomains(context.Background())
+		assert.EqualError(t, err, "tempmail: get API key: empty key")
+	})
+}
+
+func TestClient_String(t *testing.T) {
+	c := NewClient("secret-api-key", nil)
+	for _, format := range []string{"%v", "%+v", "%#v", "%s"} {
+		s := fmt.Sprintf(format, c)
+		assert.NotContains(t, s, "secret-api-key", format)
+		assert.Contains(t, s, "-key", format)
+	}
+}
+
+func TestStaticCredentials(t *testing.T) {
+	s := NewStaticCredentials("secret-api-key")
+	key, err := s.APIKey(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "secret-api-key", key)
+	assert.NotContains(t, fmt.Sprint(s), "secret")
+}
+
+func TestEnvCredentials(t *testing.T) {
+	t.Setenv("TEMPMAIL_TEST_API_KEY", "env-key")
+	key, err := EnvCredentials("TEMPMAIL_TEST_API_KEY").APIKey(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "env-key", key)
+
+	_, err = EnvCredentials("TEMPMAIL_TEST_MISSING").APIKey(context.Background())
+	assert.EqualError(t, err, "environment variable TEMPMAIL_TEST_MISSING is not set")
+}
+
+func TestFileCredentials(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api-key")
+	f := NewFileCredentials(path)
+
+	_, err := f.APIKey(context.Background())
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	require.NoError(t, os.WriteFile(path, []byte("file-key\n"), 0o600))
+	key, err := f.APIKey(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "file-key", key)
+
+	require.NoError(t, os.WriteFile(path, []byte("rotated-key\n"), 0o600))
+	key, err = f.APIKey(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "rotated-key", key)
+
+	require.NoError(t, os.WriteFile(path, []byte(" \n"), 0o600))
+	_, err = f.APIKey(context.Background())
+	assert.ErrorContains(t, err, "is empty")
+}
@@ -0,0 +1,218 @@
+package tempmail
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ErrUnknownFields is matched by the *UnknownFieldsError returned in strict decoding mode.
+var ErrUnknownFields = errors.New("tempmail: unknown fields in response")
+
+// UnknownFields describes the fields of a response that the Client doesn't know,
+// usually because the API has added or renamed a field.
+type UnknownFields struct {
+	// Operation is the Client method that received the response.
+	Operation Operation
+	// Type is the Go type the response was decoded into, for example "tempmail.Message".
+	Type string
+	// Fields are the paths of the unknown fields, for example "messages[0].priority".
+	Fields []string
+}
+
+// UnknownFieldsError is returned in strict decoding mode when a response has unknown fields.
+type UnknownFieldsError struct {
+	UnknownFields
+}
+
+// Error implements the error interface.
+func (e *UnknownFieldsError) Error() string {
+	return fmt.Sprintf("%s: %s %s: %s", ErrUnknownFields, e.Operation, e.Type, strings.Join(e.Fields, ", "))
+}
+
+// Is reports whether the target is ErrUnknownFields.
+func (e *UnknownFieldsError) Is(target error) bool {
+	return target == ErrUnknownFields
+}
+
+// WithStrictDecoding fails calls whose response has fields the Client doesn't know
+// with an *UnknownFieldsError, like json.Decoder.DisallowUnknownFields. Error responses
+// are checked too: the *UnknownFieldsError is then joined with the *HTTPError.
+// It is meant for tests that detect API schema drift, not for production use.
+func WithStrictDecoding() ClientOption {
+	return func(c *Client) {
+		c.strict = true
+	}
+}
+
+// WithUnknownFieldsHandler reports the responses that have fields the Client doesn't know,
+// without failing the call. The handler must be safe for concurrent use.
+func WithUnknownFieldsHandler(handler func(UnknownFields)) ClientOption {
+	return func(c *Client) {
+		c.onUnknownFields = handler
+	}
+}
+
+// checksFields reports whether responses are checked for unknown fields.
+func (c *Client) checksFields() bool {
+	return c.strict || c.onUnknownFields != nil
+}
+
+// decode decodes the JSON response body of the operation into v.
+func (c *Client) decode(op Operation, r io.Reader, v interface{}) error {
+	if !c.checksFields() {
+		return json.NewDecoder(r).Decode(v)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return c.decodeBytes(op, data, v)
+}
+
+// decodeBytes decodes the JSON response data of the operation into v.
+func (c *Client) decodeBytes(op Operation, data []byte, v interface{}) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+	if !c.checksFields() {
+		return nil
+	}
+	fields, err := unknownFields(data, reflect.TypeOf(v))
+	if err != nil || len(fields) == 0 {
+		return err
+	}
+	unknown := UnknownFields{
+		Operation: op,
+		Type:      strings.TrimPrefix(reflect.TypeOf(v).String(), "*"),
+		Fields:    fields,
+	}
+	if c.onUnknownFields != nil {
+		c.onUnknownFields(unknown)
+	}
+	if c.strict {
+		return &UnknownFieldsError{UnknownFields: unknown}
+	}
+	return nil
+}
+
+var unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// unknownFields returns the sorted paths of the fields of the JSON data that
+// encoding/json would ignore when decoding into a value of type t.
+func unknownFields(data []byte, t reflect.Type) ([]string, error) {
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	var fields []string
+	collectUnknownFields(raw, t, "", &fields)
+	sort.Strings(fields)
+	return fields, nil
+}
+
+// collectUnknownFields appends the paths of the unknown fields of raw to fields.
+// Types that implement json.Unmarshaler decode themselves and are not checked.
+func collectUnknownFields(raw interface{}, t reflect.Type, path string, fields *[]string) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if reflect.PointerTo(t).Implements(unmarshalerType) {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		object, ok := raw.(map[string]interface{})
+		if !ok {
+			return
+		}
+		known := jsonFields(t)
+		for name, value := range object {
+			field, ok := lookupJSONField(known, name)
+			if !ok {
+				*fields = append(*fields, joinFieldPath(path, name))
+				continue
+			}
+			collectUnknownFields(value, field, joinFieldPath(path, name), fields)
+		}
+	case reflect.Slice, reflect.Array:
+		items, ok := raw.([]interface{})
+		if !ok {
+			return
+		}
+		for i, item := range items {
+			collectUnknownFields(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i), fields)
+		}
+	case reflect.Map:
+		object, ok := raw.(map[string]interface{})
+		if !ok {
+			return
+		}
+		for key, value := range object {
+			collectUnknownFields(value, t.Elem(), joinFieldPath(path, key), fields)
+		}
+	}
+}
+
+// jsonFields returns the types of the JSON fields of the struct type by name,
+// including the fields promoted from embedded structs.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" {
+			embedded := f.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				for n, ft := range jsonFields(embedded) {
+					if _, ok := fields[n]; !ok {
+						fields[n] = ft
+					}
+				}
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+// lookupJSONField finds the field the way encoding/json does: an exact match,
+// or else a case-insensitive one.
+func lookupJSONField(fields map[string]reflect.Type, name string) (reflect.Type, bool) {
+	if t, ok := fields[name]; ok {
+		return t, true
+	}
+	for n, t := range fields {
+		if strings.EqualFold(n, name) {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// joinFieldPath appends the field name to the path.
+func joinFieldPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
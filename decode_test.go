@@ -0,0 +1,138 @@
+package tempmail
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestContract_fixtures checks that the recorded API responses in testdata
+// decode into their types without unknown fields.
+func TestContract_fixtures(t *testing.T) {
+	fixtures := map[string]interface{}{
+		"create_email.json":        &createEmailResponse{},
+		"error_response.json":      &HTTPError{},
+		"get_message.json":         &Message{},
+		"list_domains.json":        &ListDomainsResponse{},
+		"list_email_messages.json": &ListEmailMessagesResponse{},
+		"rate_limit.json":          &rateLimitResponse{},
+	}
+
+	files, err := filepath.Glob("testdata/*.json")
+	require.NoError(t, err)
+	for _, file := range files {
+		name := filepath.Base(file)
+		t.Run(name, func(t *testing.T) {
+			v, ok := fixtures[name]
+			require.True(t, ok, "no type for fixture %s", name)
+
+			c := NewClient("API_KEY", nil, WithStrictDecoding())
+			require.NoError(t, c.decodeBytes(OperationGetMessage, readFile(t, file), v))
+		})
+	}
+}
+
+func TestClient_strictDecoding(t *testing.T) {
+	message := []byte(`{"id":"1","subject":"Hi","priority":"high","attachments":[{"id":"a","checksum":"x"}]}`)
+
+	t.Run("unknown fields", func(t *testing.T) {
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(mock.Anything).Return(newTestResponse(http.StatusOK, message), nil).Once()
+
+		c := NewClient("API_KEY", nil, WithStrictDecoding())
+		c.doer = mDoer
+		_, _, err := c.GetMessage(context.Background(), "1")
+		require.ErrorIs(t, err, ErrUnknownFields)
+		var unknown *UnknownFieldsError
+		require.ErrorAs(t, err, &unknown)
+		assert.Equal(t, UnknownFields{
+			Operation: OperationGetMessage,
+			Type:      "tempmail.Message",
+			Fields:    []string{"attachments[0].checksum", "priority"},
+		}, unknown.UnknownFields)
+	})
+
+	t.Run("error response", func(t *testing.T) {
+		body := []byte(`{"error":{"type":"request_error","code":"not_found","detail":"Not found","hint":"x"},"meta":{}}`)
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(mock.Anything).Return(newTestResponse(http.StatusNotFound, body), nil).Once()
+
+		c := NewClient("API_KEY", nil, WithStrictDecoding())
+		c.doer = mDoer
+		_, err := c.DeleteMessage(context.Background(), "1")
+		assert.ErrorIs(t, err, ErrUnknownFields)
+		var httpErr *HTTPError
+		require.ErrorAs(t, err, &httpErr)
+		assert.Equal(t, "not_found", httpErr.ErrorDetails.Code)
+	})
+
+	t.Run("known fields", func(t *testing.T) {
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(mock.Anything).Return(newTestResponse(http.StatusOK, readFile(t, "testdata/list_email_messages.json")), nil).Once()
+
+		c := NewClient("API_KEY", nil, WithStrictDecoding())
+		c.doer = mDoer
+		_, _, err := c.ListEmailMessages(context.Background(), "user@example.com")
+		require.NoError(t, err)
+	})
+}
+
+func TestClient_unknownFieldsHandler(t *testing.T) {
+	var mu sync.Mutex
+	var reported []UnknownFields
+	mDoer := newMockDoer(t)
+	mDoer.EXPECT().Do(mock.Anything).Return(newTestResponse(http.StatusOK, []byte(`{"domains":[{"name":"example.com","type":"public","tier":"gold"}]}`)), nil).Once()
+
+	c := NewClient("API_KEY", nil, WithUnknownFieldsHandler(func(u UnknownFields) {
+		mu.Lock()
+		defer mu.Unlock()
+		reported = append(reported, u)
+	}))
+	c.doer = mDoer
+	domains, _, err := c.ListDomains(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, domains.Domains, 1)
+	assert.Equal(t, []UnknownFields{{
+		Operation: OperationListDomains,
+		Type:      "tempmail.ListDomainsResponse",
+		Fields:    []string{"domains[0].tier"},
+	}}, reported)
+}
+
+func TestUnknownFields(t *testing.T) {
+	type embedded struct {
+		Inner string `json:"inner"`
+	}
+	type value struct {
+		embedded
+		Name     string               `json:"name"`
+		Plain    int                  // matched by its Go name
+		Ignored  string               `json:"-"`
+		Type     DomainType           `json:"type"`
+		Time     time.Time            `json:"time"`
+		Children map[string]*embedded `json:"children"`
+		Any      interface{}          `json:"any"`
+	}
+
+	fields, err := unknownFields([]byte(`{
+		"inner": "a",
+		"NAME": "b",
+		"plain": 1,
+		"Ignored": "c",
+		"type": {"nested": true},
+		"time": "2025-01-01T00:00:00Z",
+		"children": {"x": {"inner": "d", "extra": 1}},
+		"any": {"anything": 1},
+		"new": null
+	}`), reflect.TypeOf(&value{}))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Ignored", "children.x.extra", "new"}, fields)
+}
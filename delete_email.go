@@ -2,21 +2,39 @@ package tempmail
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"net/http"
 )
 
 // DeleteEmail deletes an email address.
+// It returns ErrEmailExpired if the email address was created by the Client and has expired.
+// An expired address is still removed from the Store.
 func (c *Client) DeleteEmail(ctx context.Context, email string) (*Response, error) {
-	req, err := c.newRequest(ctx, http.MethodDelete, fmt.Sprintf("/v1/emails/%s", email), nil)
+	if err := validateEmail(email); err != nil {
+		return nil, err
+	}
+	if err := c.known.check(email, c.now()); err != nil {
+		// The address is gone, so it must not stay in the cache or the Store either.
+		c.invalidateEmail(ctx, email)
+		return nil, errors.Join(err, c.storeDeleted(ctx, email))
+	}
+	req, err := c.newRequest(ctx, OperationDeleteEmail, http.MethodDelete, buildPath("v1", "emails", email), nil)
 	if err != nil {
 		return nil, err
 	}
 
 	r, err := c.do(req, nil)
 	if err != nil {
+		if isNotFound(err) {
+			c.invalidateEmail(ctx, email)
+		}
 		return nil, err
 	}
+	c.known.remove(email)
+	c.invalidateEmail(ctx, email)
+	if err := c.storeDeleted(ctx, email); err != nil {
+		return r, err
+	}
 
 	return r, nil
 }
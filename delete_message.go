@@ -2,21 +2,27 @@ package tempmail
 
 import (
 	"context"
-	"fmt"
 	"net/http"
 )
 
 // DeleteMessage deletes a message by its ID.
 func (c *Client) DeleteMessage(ctx context.Context, messageID string) (*Response, error) {
-	req, err := c.newRequest(ctx, http.MethodDelete, fmt.Sprintf("/v1/messages/%s", messageID), nil)
+	if err := validateID("messageID", messageID); err != nil {
+		return nil, err
+	}
+	req, err := c.newRequest(ctx, OperationDeleteMessage, http.MethodDelete, buildPath("v1", "messages", messageID), nil)
 	if err != nil {
 		return nil, err
 	}
 
 	r, err := c.do(req, nil)
 	if err != nil {
+		if isNotFound(err) {
+			c.invalidateMessages(ctx, messageID)
+		}
 		return nil, err
 	}
+	c.invalidateMessages(ctx, messageID)
 
 	return r, nil
 }
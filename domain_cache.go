@@ -0,0 +1,158 @@
+package tempmail
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultDomainCacheTTL is the default time DomainCache serves domains without refreshing them.
+const DefaultDomainCacheTTL = 5 * time.Minute
+
+// DomainCacheOptions represents the options to create a DomainCache.
+type DomainCacheOptions struct {
+	// TTL is the time the domains are served without refreshing them. Defaults to 5 minutes.
+	TTL time.Duration
+	// StaleTTL is the time after TTL during which the old domains are still served
+	// while they are refreshed in the background. Disabled if zero.
+	StaleTTL time.Duration
+	// OnRefreshError is called when a background refresh fails. Optional.
+	OnRefreshError func(error)
+}
+
+// DomainCache caches the result of ListDomains. Concurrent refreshes are
+// deduplicated into a single call. It implements DomainLister, so it can be
+// passed to NewDomainSelector. It is safe for concurrent use.
+type DomainCache struct {
+	lister DomainLister
+	opts   DomainCacheOptions
+	now    func() time.Time
+
+	mu        sync.Mutex
+	resp      ListDomainsResponse
+	r         *Response
+	fetchedAt time.Time
+	valid     bool
+	// generation is incremented by Invalidate, so refreshes started before are not stored.
+	generation int
+	call       *domainCall
+}
+
+// domainCall is an in-flight ListDomains call shared by concurrent callers.
+type domainCall struct {
+	done chan struct{}
+	resp ListDomainsResponse
+	r    *Response
+	err  error
+}
+
+// NewDomainCache creates a DomainCache that lists domains with the given lister,
+// for example a Client.
+func NewDomainCache(lister DomainLister, opts DomainCacheOptions) *DomainCache {
+	if opts.TTL <= 0 {
+		opts.TTL = DefaultDomainCacheTTL
+	}
+	return &DomainCache{
+		lister: lister,
+		opts:   opts,
+		now:    time.Now,
+	}
+}
+
+// ListDomains returns the cached domains. If they are older than TTL but within
+// StaleTTL, they are returned and refreshed in the background. Otherwise they are
+// fetched before returning. Errors are not cached.
+// The returned *Response is the one of the call that populated the cache.
+func (d *DomainCache) ListDomains(ctx context.Context) (ListDomainsResponse, *Response, error) {
+	d.mu.Lock()
+	if d.valid {
+		age := d.now().Sub(d.fetchedAt)
+		if age < d.opts.TTL {
+			resp, r := d.cached()
+			d.mu.Unlock()
+			return resp, r, nil
+		}
+		if age < d.opts.TTL+d.opts.StaleTTL {
+			resp, r := d.cached()
+			if d.call == nil {
+				call := d.start(ctx)
+				go d.reportRefresh(call)
+			}
+			d.mu.Unlock()
+			return resp, r, nil
+		}
+	}
+	call := d.call
+	if call == nil {
+		call = d.start(ctx)
+	}
+	d.mu.Unlock()
+
+	select {
+	case <-call.done:
+		if call.err != nil {
+			return ListDomainsResponse{}, nil, call.err
+		}
+		return copyDomains(call.resp), call.r, nil
+	case <-ctx.Done():
+		return ListDomainsResponse{}, nil, ctx.Err()
+	}
+}
+
+// Invalidate drops the cached domains, so the next ListDomains call fetches them.
+func (d *DomainCache) Invalidate() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.valid = false
+	d.resp, d.r = ListDomainsResponse{}, nil
+	d.generation++
+	d.call = nil
+}
+
+// cached returns a copy of the cached domains. d.mu must be held.
+func (d *DomainCache) cached() (ListDomainsResponse, *Response) {
+	return copyDomains(d.resp), d.r
+}
+
+// start starts a call to the lister. d.mu must be held.
+// The call isn't canceled with ctx, because other callers may wait for it.
+func (d *DomainCache) start(ctx context.Context) *domainCall {
+	call := &domainCall{done: make(chan struct{})}
+	d.call = call
+	generation := d.generation
+
+	go func() {
+		resp, r, err := d.lister.ListDomains(context.WithoutCancel(ctx))
+		call.resp, call.r, call.err = resp, r, err
+
+		d.mu.Lock()
+		if d.generation == generation {
+			d.call = nil
+			if err == nil {
+				d.resp, d.r = copyDomains(resp), r
+				d.fetchedAt = d.now()
+				d.valid = true
+			}
+		}
+		d.mu.Unlock()
+		close(call.done)
+	}()
+	return call
+}
+
+// reportRefresh passes the error of a background refresh to OnRefreshError.
+func (d *DomainCache) reportRefresh(call *domainCall) {
+	<-call.done
+	if call.err != nil && d.opts.OnRefreshError != nil {
+		d.opts.OnRefreshError(call.err)
+	}
+}
+
+// copyDomains copies the domains, so callers can't modify the cache.
+func copyDomains(resp ListDomainsResponse) ListDomainsResponse {
+	if resp.Domains == nil {
+		return resp
+	}
+	return ListDomainsResponse{Domains: append([]ListDomainsDomainResponse(nil), resp.Domains...)}
+}
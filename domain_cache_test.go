@@ -0,0 +1,186 @@
+package tempmail
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingLister is a DomainLister returning a domain named after the call number.
+type countingLister struct {
+	calls   atomic.Int32
+	release chan struct{}
+	err     error
+}
+
+func (l *countingLister) ListDomains(context.Context) (ListDomainsResponse, *Response, error) {
+	n := l.calls.Add(1)
+	if l.release != nil {
+		<-l.release
+	}
+	if l.err != nil {
+		return ListDomainsResponse{}, nil, l.err
+	}
+	return ListDomainsResponse{Domains: []ListDomainsDomainResponse{
+		{Name: string(rune('a'+n-1)) + ".com", Type: DomainTypePublic},
+	}}, &Response{}, nil
+}
+
+// newTestDomainCache creates a DomainCache with a clock that can be moved by the returned function.
+func newTestDomainCache(lister DomainLister, opts DomainCacheOptions) (*DomainCache, func(time.Duration)) {
+	var mu sync.Mutex
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	d := NewDomainCache(lister, opts)
+	d.now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+	return d, func(dur time.Duration) {
+		mu.Lock()
+		defer mu.Unlock()
+		now = now.Add(dur)
+	}
+}
+
+func domainName(t *testing.T, d *DomainCache) string {
+	resp, _, err := d.ListDomains(context.Background())
+	require.NoError(t, err)
+	require.Len(t, resp.Domains, 1)
+	return resp.Domains[0].Name
+}
+
+func TestDomainCache_ListDomains(t *testing.T) {
+	t.Run("TTL", func(t *testing.T) {
+		lister := &countingLister{}
+		d, advance := newTestDomainCache(lister, DomainCacheOptions{TTL: time.Minute})
+
+		assert.Equal(t, "a.com", domainName(t, d))
+		advance(59 * time.Second)
+		assert.Equal(t, "a.com", domainName(t, d))
+		assert.EqualValues(t, 1, lister.calls.Load())
+
+		advance(time.Second)
+		assert.Equal(t, "b.com", domainName(t, d))
+		assert.EqualValues(t, 2, lister.calls.Load())
+	})
+
+	t.Run("stale while revalidate", func(t *testing.T) {
+		lister := &countingLister{}
+		d, advance := newTestDomainCache(lister, DomainCacheOptions{TTL: time.Minute, StaleTTL: time.Minute})
+		assert.Equal(t, "a.com", domainName(t, d))
+
+		advance(90 * time.Second)
+		lister.release = make(chan struct{})
+		assert.Equal(t, "a.com", domainName(t, d))
+		assert.Equal(t, "a.com", domainName(t, d))
+		close(lister.release)
+
+		assert.Eventually(t, func() bool {
+			d.mu.Lock()
+			defer d.mu.Unlock()
+			return d.call == nil
+		}, time.Second, time.Millisecond)
+		assert.Equal(t, "b.com", domainName(t, d))
+		assert.EqualValues(t, 2, lister.calls.Load())
+
+		advance(2 * time.Minute)
+		assert.Equal(t, "c.com", domainName(t, d))
+	})
+
+	t.Run("singleflight", func(t *testing.T) {
+		lister := &countingLister{release: make(chan struct{})}
+		d, _ := newTestDomainCache(lister, DomainCacheOptions{})
+
+		var wg sync.WaitGroup
+		names := make([]string, 10)
+		for i := range names {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				names[i] = domainName(t, d)
+			}(i)
+		}
+		assert.Eventually(t, func() bool { return lister.calls.Load() == 1 }, time.Second, time.Millisecond)
+		close(lister.release)
+		wg.Wait()
+
+		for _, name := range names {
+			assert.Equal(t, "a.com", name)
+		}
+		assert.EqualValues(t, 1, lister.calls.Load())
+	})
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		lister := &countingLister{err: assert.AnError}
+		d, _ := newTestDomainCache(lister, DomainCacheOptions{})
+
+		_, _, err := d.ListDomains(context.Background())
+		assert.ErrorIs(t, err, assert.AnError)
+		_, _, err = d.ListDomains(context.Background())
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.EqualValues(t, 2, lister.calls.Load())
+	})
+
+	t.Run("background refresh error", func(t *testing.T) {
+		lister := &countingLister{}
+		errs := make(chan error, 1)
+		d, advance := newTestDomainCache(lister, DomainCacheOptions{
+			TTL:            time.Minute,
+			StaleTTL:       time.Minute,
+			OnRefreshError: func(err error) { errs <- err },
+		})
+		assert.Equal(t, "a.com", domainName(t, d))
+
+		lister.err = assert.AnError
+		advance(90 * time.Second)
+		assert.Equal(t, "a.com", domainName(t, d))
+		assert.ErrorIs(t, <-errs, assert.AnError)
+	})
+
+	t.Run("context canceled", func(t *testing.T) {
+		lister := &countingLister{release: make(chan struct{})}
+		defer close(lister.release)
+		d, _ := newTestDomainCache(lister, DomainCacheOptions{})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, _, err := d.ListDomains(ctx)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("returns a copy", func(t *testing.T) {
+		d, _ := newTestDomainCache(&countingLister{}, DomainCacheOptions{})
+		resp, _, err := d.ListDomains(context.Background())
+		require.NoError(t, err)
+		resp.Domains[0].Name = "changed.com"
+		assert.Equal(t, "a.com", domainName(t, d))
+	})
+}
+
+func TestDomainCache_Invalidate(t *testing.T) {
+	lister := &countingLister{}
+	d, _ := newTestDomainCache(lister, DomainCacheOptions{})
+
+	assert.Equal(t, "a.com", domainName(t, d))
+	d.Invalidate()
+	assert.Equal(t, "b.com", domainName(t, d))
+	assert.Equal(t, "b.com", domainName(t, d))
+	assert.EqualValues(t, 2, lister.calls.Load())
+}
+
+func TestDomainCache_selector(t *testing.T) {
+	lister := &countingLister{}
+	s := NewDomainSelector(NewDomainCache(lister, DomainCacheOptions{}), DomainSelectorOptions{})
+	for i := 0; i < 3; i++ {
+		d, err := s.Select(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "a.com", d.Name)
+	}
+	assert.EqualValues(t, 1, lister.calls.Load())
+}
@@ -0,0 +1,135 @@
+package tempmail
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNoDomain is returned by DomainSelector.Select when no domain matches the options.
+var ErrNoDomain = errors.New("tempmail: no domain available")
+
+// DomainLister lists the available domains. It is implemented by Client.
+type DomainLister interface {
+	ListDomains(ctx context.Context) (ListDomainsResponse, *Response, error)
+}
+
+// DomainStrategy is the way DomainSelector picks a domain.
+type DomainStrategy int
+
+const (
+	// DomainStrategyRandom picks a random domain.
+	DomainStrategyRandom DomainStrategy = iota
+	// DomainStrategyRoundRobin cycles through the domains ordered by name.
+	DomainStrategyRoundRobin
+	// DomainStrategyPreferCustom picks a random custom domain, or a random domain of another type if there is none.
+	DomainStrategyPreferCustom
+)
+
+// DomainSelectorOptions represents the options to create a DomainSelector.
+type DomainSelectorOptions struct {
+	// Strategy is the way a domain is picked. Defaults to DomainStrategyRandom.
+	Strategy DomainStrategy
+	// Types restricts the domains to the given types. All types are allowed if empty.
+	Types []DomainType
+	// Exclude are the domain names that are never picked, compared case-insensitively.
+	Exclude []string
+	// Seed makes random picks reproducible when non-zero.
+	Seed int64
+}
+
+// DomainSelector picks domains from the result of ListDomains, so signups can be spread
+// across domains. It is safe for concurrent use.
+type DomainSelector struct {
+	lister DomainLister
+	opts   DomainSelectorOptions
+
+	mu   sync.Mutex
+	rnd  *rand.Rand
+	next int
+}
+
+// NewDomainSelector creates a DomainSelector that lists domains with the given lister,
+// for example a Client.
+func NewDomainSelector(lister DomainLister, opts DomainSelectorOptions) *DomainSelector {
+	seed := opts.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &DomainSelector{
+		lister: lister,
+		opts:   opts,
+		rnd:    rand.New(rand.NewSource(seed)), //nolint:gosec // Domain selection doesn't need a cryptographic source.
+	}
+}
+
+// Select lists the domains and picks one according to the options.
+// It returns ErrNoDomain if no domain matches.
+func (s *DomainSelector) Select(ctx context.Context) (ListDomainsDomainResponse, error) {
+	resp, _, err := s.lister.ListDomains(ctx)
+	if err != nil {
+		return ListDomainsDomainResponse{}, err
+	}
+
+	domains := s.filter(resp.Domains)
+	if len(domains) == 0 {
+		return ListDomainsDomainResponse{}, ErrNoDomain
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch s.opts.Strategy {
+	case DomainStrategyRoundRobin:
+		sort.Slice(domains, func(i, j int) bool {
+			return domains[i].Name < domains[j].Name
+		})
+		d := domains[s.next%len(domains)]
+		s.next++
+		return d, nil
+	case DomainStrategyPreferCustom:
+		var custom []ListDomainsDomainResponse
+		for _, d := range domains {
+			if d.Type == DomainTypeCustom {
+				custom = append(custom, d)
+			}
+		}
+		if len(custom) > 0 {
+			domains = custom
+		}
+	}
+	return domains[s.rnd.Intn(len(domains))], nil
+}
+
+// filter returns the domains allowed by the options.
+func (s *DomainSelector) filter(domains []ListDomainsDomainResponse) []ListDomainsDomainResponse {
+	result := make([]ListDomainsDomainResponse, 0, len(domains))
+	for _, d := range domains {
+		if s.allowed(d) {
+			result = append(result, d)
+		}
+	}
+	return result
+}
+
+// allowed reports whether the domain matches the type and exclude options.
+func (s *DomainSelector) allowed(d ListDomainsDomainResponse) bool {
+	for _, name := range s.opts.Exclude {
+		if strings.EqualFold(name, d.Name) {
+			return false
+		}
+	}
+	if len(s.opts.Types) == 0 {
+		return true
+	}
+	for _, t := range s.opts.Types {
+		if t == d.Type {
+			return true
+		}
+	}
+	return false
+}
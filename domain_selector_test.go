@@ -0,0 +1,84 @@
+package tempmail
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// staticDomains is a DomainLister returning fixed domains.
+type staticDomains struct {
+	domains []ListDomainsDomainResponse
+	err     error
+}
+
+func (s staticDomains) ListDomains(context.Context) (ListDomainsResponse, *Response, error) {
+	return ListDomainsResponse{Domains: s.domains}, nil, s.err
+}
+
+var testDomains = staticDomains{domains: []ListDomainsDomainResponse{
+	{Name: "b.com", Type: DomainTypePublic},
+	{Name: "a.com", Type: DomainTypePublic},
+	{Name: "premium.com", Type: DomainTypePremium},
+	{Name: "custom.com", Type: DomainTypeCustom},
+}}
+
+// selectNames selects n domains and returns their names.
+func selectNames(t *testing.T, s *DomainSelector, n int) []string {
+	names := make([]string, 0, n)
+	for i := 0; i < n; i++ {
+		d, err := s.Select(context.Background())
+		require.NoError(t, err)
+		names = append(names, d.Name)
+	}
+	return names
+}
+
+func TestDomainSelector_Select(t *testing.T) {
+	t.Run("random", func(t *testing.T) {
+		s := NewDomainSelector(testDomains, DomainSelectorOptions{Seed: 1})
+		names := selectNames(t, s, 100)
+		assert.Subset(t, []string{"a.com", "b.com", "premium.com", "custom.com"}, names)
+		assert.Subset(t, names, []string{"a.com", "b.com", "premium.com", "custom.com"})
+
+		// The same seed gives the same picks.
+		assert.Equal(t, names, selectNames(t, NewDomainSelector(testDomains, DomainSelectorOptions{Seed: 1}), 100))
+	})
+
+	t.Run("round robin", func(t *testing.T) {
+		s := NewDomainSelector(testDomains, DomainSelectorOptions{
+			Strategy: DomainStrategyRoundRobin,
+			Types:    []DomainType{DomainTypePublic, DomainTypeCustom},
+		})
+		assert.Equal(t, []string{"a.com", "b.com", "custom.com", "a.com"}, selectNames(t, s, 4))
+	})
+
+	t.Run("prefer custom", func(t *testing.T) {
+		s := NewDomainSelector(testDomains, DomainSelectorOptions{Strategy: DomainStrategyPreferCustom})
+		assert.Equal(t, []string{"custom.com", "custom.com"}, selectNames(t, s, 2))
+
+		s = NewDomainSelector(testDomains, DomainSelectorOptions{
+			Strategy: DomainStrategyPreferCustom,
+			Exclude:  []string{"Custom.com", "a.com", "b.com"},
+		})
+		assert.Equal(t, []string{"premium.com"}, selectNames(t, s, 1))
+	})
+
+	t.Run("no domain", func(t *testing.T) {
+		s := NewDomainSelector(testDomains, DomainSelectorOptions{Types: []DomainType{"unknown"}})
+		_, err := s.Select(context.Background())
+		assert.ErrorIs(t, err, ErrNoDomain)
+	})
+
+	t.Run("error from ListDomains", func(t *testing.T) {
+		s := NewDomainSelector(staticDomains{err: assert.AnError}, DomainSelectorOptions{})
+		_, err := s.Select(context.Background())
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+
+	t.Run("client is a DomainLister", func(t *testing.T) {
+		var _ DomainLister = newClient()
+	})
+}
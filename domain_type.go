@@ -0,0 +1,47 @@
+package tempmail
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// DomainType is the type of a domain.
+type DomainType string
+
+const (
+	// DomainTypePublic is a public domain.
+	DomainTypePublic DomainType = "public"
+	// DomainTypeCustom is user-provided domain.
+	DomainTypeCustom DomainType = "custom"
+	// DomainTypePremium is a premium domain.
+	DomainTypePremium DomainType = "premium"
+)
+
+// Valid reports whether the domain type is one of the known types.
+func (t DomainType) Valid() bool {
+	switch t {
+	case DomainTypePublic, DomainTypeCustom, DomainTypePremium:
+		return true
+	}
+	return false
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// Known types are matched case-insensitively. Unknown types are kept as-is instead of failing,
+// so new domain types added by the API don't break decoding. Use Valid to check the result.
+func (t *DomainType) UnmarshalJSON(b []byte) error {
+	var s *string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	if s == nil {
+		*t = ""
+		return nil
+	}
+	if known := DomainType(strings.ToLower(*s)); known.Valid() {
+		*t = known
+		return nil
+	}
+	*t = DomainType(*s)
+	return nil
+}
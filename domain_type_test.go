@@ -0,0 +1,48 @@
+package tempmail
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDomainType_Valid(t *testing.T) {
+	assert.True(t, DomainTypePublic.Valid())
+	assert.True(t, DomainTypeCustom.Valid())
+	assert.True(t, DomainTypePremium.Valid())
+	assert.False(t, DomainType("pubilc").Valid())
+	assert.False(t, DomainType("").Valid())
+}
+
+func TestDomainType_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected DomainType
+	}{
+		{input: `"public"`, expected: DomainTypePublic},
+		{input: `"Premium"`, expected: DomainTypePremium},
+		{input: `"enterprise"`, expected: DomainType("enterprise")},
+		{input: `null`, expected: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var d ListDomainsDomainResponse
+			require.NoError(t, json.Unmarshal([]byte(`{"name":"example.com","type":`+tt.input+`}`), &d))
+			assert.Equal(t, tt.expected, d.Type)
+		})
+	}
+
+	t.Run("not a string", func(t *testing.T) {
+		var d DomainType
+		assert.Error(t, json.Unmarshal([]byte(`1`), &d))
+	})
+}
+
+func TestClient_CreateEmail_invalidDomainType(t *testing.T) {
+	c := newClient()
+	_, _, err := c.CreateEmail(context.Background(), CreateEmailOptions{DomainType: "pubilc"})
+	assert.EqualError(t, err, `tempmail: invalid domainType "pubilc": unknown domain type`)
+}
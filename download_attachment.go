@@ -2,29 +2,25 @@ package tempmail
 
 import (
 	"context"
-	"fmt"
 	"io"
 	"net/http"
 )
 
 // DownloadAttachment downloads an attachment by its ID and returns the raw bytes.
 func (c *Client) DownloadAttachment(ctx context.Context, attachmentID string) ([]byte, *Response, error) {
-	req, err := c.newRequest(ctx, http.MethodGet, fmt.Sprintf("/v1/attachments/%s", attachmentID), nil)
-	if err != nil {
+	if err := validateID("attachmentID", attachmentID); err != nil {
 		return nil, nil, err
 	}
-
-	r, err := c.rawDo(req)
+	req, err := c.newRequest(ctx, OperationDownloadAttachment, http.MethodGet, buildPath("v1", "attachments", attachmentID), nil)
 	if err != nil {
 		return nil, nil, err
 	}
-	defer r.Body.Close()
-
-	if err := c.checkResponse(r); err != nil {
-		return nil, nil, err
-	}
 
-	b, err := io.ReadAll(r.Body)
+	var b []byte
+	r, err := c.send(req, func(r *Response) error {
+		b, err = io.ReadAll(r.Body)
+		return err
+	})
 	if err != nil {
 		return nil, nil, err
 	}
@@ -0,0 +1,139 @@
+package tempmail
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrEmailExpired is returned by operations on an email address created by the Client
+// after its TTL has passed.
+var ErrEmailExpired = errors.New("tempmail: email address expired")
+
+// KnownEmail represents an email address created by the Client that hasn't expired yet.
+type KnownEmail struct {
+	// Email is the email address.
+	Email string
+	// ExpiresAt is the time at which the email address expires.
+	ExpiresAt time.Time
+}
+
+// ExpiryNotifier is called shortly before a known email address expires.
+type ExpiryNotifier func(email KnownEmail)
+
+// WithExpiryNotification makes the Client call notify the given duration before
+// an email address created by the Client expires.
+// notify is called from its own goroutine.
+func WithExpiryNotification(before time.Duration, notify ExpiryNotifier) ClientOption {
+	return func(c *Client) {
+		c.known.notifyBefore = before
+		c.known.notify = notify
+	}
+}
+
+// Known returns the email addresses created by the Client that haven't expired
+// or been deleted yet, ordered by expiry time.
+func (c *Client) Known() []KnownEmail {
+	return c.known.list(c.now())
+}
+
+// registry tracks the expiry of the email addresses created by the Client.
+type registry struct {
+	notifyBefore time.Duration
+	notify       ExpiryNotifier
+
+	mu      sync.Mutex
+	entries map[string]*registryEntry
+}
+
+type registryEntry struct {
+	expiresAt time.Time
+	timer     *time.Timer
+}
+
+func newRegistry() *registry {
+	return &registry{entries: make(map[string]*registryEntry)}
+}
+
+// add starts tracking the email address.
+func (r *registry) add(email string, expiresAt time.Time, now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.stop(email)
+	entry := &registryEntry{expiresAt: expiresAt}
+	if r.notify != nil {
+		known := KnownEmail{Email: email, ExpiresAt: expiresAt}
+		entry.timer = time.AfterFunc(expiresAt.Add(-r.notifyBefore).Sub(now), func() {
+			r.notify(known)
+		})
+	}
+	r.entries[email] = entry
+}
+
+// expiry returns the time at which the email address expires, if it is tracked.
+func (r *registry) expiry(email string) (time.Time, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry, ok := r.entries[email]
+	if !ok {
+		return time.Time{}, false
+	}
+	return entry.expiresAt, true
+}
+
+// remove stops tracking the email address.
+func (r *registry) remove(email string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stop(email)
+}
+
+// stop stops the notification timer of the email address and forgets it.
+// r.mu must be held.
+func (r *registry) stop(email string) {
+	if entry, ok := r.entries[email]; ok {
+		if entry.timer != nil {
+			entry.timer.Stop()
+		}
+		delete(r.entries, email)
+	}
+}
+
+// check returns ErrEmailExpired if the email address is known to be expired.
+// Unknown email addresses are not checked.
+func (r *registry) check(email string, now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entry, ok := r.entries[email]
+	if !ok || now.Before(entry.expiresAt) {
+		return nil
+	}
+	r.stop(email)
+	return fmt.Errorf("%w: %s", ErrEmailExpired, email)
+}
+
+// list returns the email addresses that haven't expired yet, ordered by expiry time.
+func (r *registry) list(now time.Time) []KnownEmail {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := make([]KnownEmail, 0, len(r.entries))
+	for email, entry := range r.entries {
+		if !now.Before(entry.expiresAt) {
+			r.stop(email)
+			continue
+		}
+		result = append(result, KnownEmail{Email: email, ExpiresAt: entry.expiresAt})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].ExpiresAt.Equal(result[j].ExpiresAt) {
+			return result[i].Email < result[j].Email
+		}
+		return result[i].ExpiresAt.Before(result[j].ExpiresAt)
+	})
+	return result
+}
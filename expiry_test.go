@@ -0,0 +1,123 @@
+package tempmail
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newExpiryTestClient returns a client with a controllable clock that has created test@example.com.
+func newExpiryTestClient(t *testing.T, mDoer *mockDoer, opts ...ClientOption) (*Client, *time.Time) {
+	mDoer.EXPECT().Do(isRequest(http.MethodPost, "/v1/emails")).
+		Return(newTestResponse(http.StatusOK, readFile(t, "testdata/create_email.json")), nil).Once()
+
+	now := time.Now()
+	c := NewClient("API_KEY", nil, opts...)
+	c.doer = mDoer
+	c.now = func() time.Time { return now }
+	_, _, err := c.CreateEmail(context.Background(), CreateEmailOptions{})
+	require.NoError(t, err)
+	return c, &now
+}
+
+func TestClient_Known(t *testing.T) {
+	t.Run("lists live addresses", func(t *testing.T) {
+		c, now := newExpiryTestClient(t, newMockDoer(t))
+		assert.Equal(t, []KnownEmail{{Email: "test@example.com", ExpiresAt: now.Add(time.Hour)}}, c.Known())
+
+		*now = now.Add(time.Hour)
+		assert.Empty(t, c.Known())
+	})
+
+	t.Run("ordered by expiry", func(t *testing.T) {
+		c := newClient()
+		now := time.Now()
+		c.known.add("b@example.com", now.Add(time.Minute), now)
+		c.known.add("c@example.com", now.Add(time.Hour), now)
+		c.known.add("a@example.com", now.Add(time.Minute), now)
+		assert.Equal(t, []KnownEmail{
+			{Email: "a@example.com", ExpiresAt: now.Add(time.Minute)},
+			{Email: "b@example.com", ExpiresAt: now.Add(time.Minute)},
+			{Email: "c@example.com", ExpiresAt: now.Add(time.Hour)},
+		}, c.Known())
+	})
+
+	t.Run("deleted addresses are forgotten", func(t *testing.T) {
+		mDoer := newMockDoer(t)
+		c, _ := newExpiryTestClient(t, mDoer)
+		mDoer.EXPECT().Do(isRequest(http.MethodDelete, "/v1/emails/test@example.com")).
+			Return(newTestResponse(http.StatusOK, []byte("{}")), nil)
+
+		_, err := c.DeleteEmail(context.Background(), "test@example.com")
+		require.NoError(t, err)
+		assert.Empty(t, c.Known())
+	})
+}
+
+func TestClient_expiredEmail(t *testing.T) {
+	t.Run("ListEmailMessages", func(t *testing.T) {
+		c, now := newExpiryTestClient(t, newMockDoer(t))
+		*now = now.Add(time.Hour)
+
+		_, _, err := c.ListEmailMessages(context.Background(), "test@example.com")
+		assert.ErrorIs(t, err, ErrEmailExpired)
+		assert.EqualError(t, err, "tempmail: email address expired: test@example.com")
+	})
+
+	t.Run("DeleteEmail", func(t *testing.T) {
+		c, now := newExpiryTestClient(t, newMockDoer(t))
+		*now = now.Add(2 * time.Hour)
+
+		_, err := c.DeleteEmail(context.Background(), "test@example.com")
+		assert.ErrorIs(t, err, ErrEmailExpired)
+	})
+
+	t.Run("unknown addresses are not checked", func(t *testing.T) {
+		mDoer := newMockDoer(t)
+		c, now := newExpiryTestClient(t, mDoer)
+		*now = now.Add(2 * time.Hour)
+		mDoer.EXPECT().Do(isRequest(http.MethodGet, "/v1/emails/other@example.com/messages")).
+			Return(newTestResponse(http.StatusOK, readFile(t, "testdata/list_email_messages.json")), nil)
+
+		_, _, err := c.ListEmailMessages(context.Background(), "other@example.com")
+		assert.NoError(t, err)
+	})
+}
+
+func TestWithExpiryNotification(t *testing.T) {
+	notified := make(chan KnownEmail, 1)
+	mDoer := newMockDoer(t)
+	mDoer.EXPECT().Do(isRequest(http.MethodPost, "/v1/emails")).
+		Return(newTestResponse(http.StatusOK, []byte(`{"email":"test@example.com","ttl":1}`)), nil)
+
+	c := NewClient("API_KEY", nil, WithExpiryNotification(990*time.Millisecond, func(e KnownEmail) {
+		notified <- e
+	}))
+	c.doer = mDoer
+	result, _, err := c.CreateEmail(context.Background(), CreateEmailOptions{})
+	require.NoError(t, err)
+
+	select {
+	case e := <-notified:
+		assert.Equal(t, KnownEmail{Email: "test@example.com", ExpiresAt: result.ExpiresAt}, e)
+	case <-time.After(time.Second):
+		t.Fatal("expiry notification was not sent")
+	}
+}
+
+func TestRegistry_remove(t *testing.T) {
+	called := false
+	r := newRegistry()
+	r.notify = func(KnownEmail) { called = true }
+	now := time.Now()
+	r.add("test@example.com", now.Add(20*time.Millisecond), now)
+	r.remove("test@example.com")
+
+	time.Sleep(40 * time.Millisecond)
+	assert.False(t, called)
+	assert.Empty(t, r.list(now))
+}
@@ -0,0 +1,140 @@
+package tempmail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultGeneratorAlphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+	defaultGeneratorLength   = 10
+	defaultGeneratorTemplate = "{prefix}-{rand}-{suffix}"
+
+	// maxGeneratorAttempts is the number of addresses CreateEmailWithGenerator tries.
+	maxGeneratorAttempts = 5
+)
+
+// GeneratorOptions represents the options to create a Generator.
+type GeneratorOptions struct {
+	// Prefix replaces {prefix} in the template, for example "ci".
+	Prefix string
+	// Suffix replaces {suffix} in the template.
+	Suffix string
+	// Alphabet is the set of characters of the random part.
+	// Defaults to lowercase letters and digits.
+	Alphabet string
+	// Length is the length of the random part. Defaults to 10.
+	Length int
+	// Template is the layout of the local part. It supports the {prefix}, {suffix},
+	// {date} (UTC, as 20060102) and {rand} placeholders. Separators left over from
+	// empty placeholders at either end are trimmed. Defaults to "{prefix}-{rand}-{suffix}".
+	Template string
+	// Seed makes the random part reproducible when non-zero.
+	Seed int64
+}
+
+// Generator generates local parts of email addresses. It is safe for concurrent use.
+type Generator struct {
+	opts GeneratorOptions
+	now  func() time.Time
+
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+// NewGenerator creates a Generator. It returns a *ValidationError if the options
+// can't produce a valid local part.
+func NewGenerator(opts GeneratorOptions) (*Generator, error) {
+	if opts.Alphabet == "" {
+		opts.Alphabet = defaultGeneratorAlphabet
+	}
+	if opts.Length <= 0 {
+		opts.Length = defaultGeneratorLength
+	}
+	if opts.Template == "" {
+		opts.Template = defaultGeneratorTemplate
+	}
+	for i := 0; i < len(opts.Alphabet); i++ {
+		if !isAtext(opts.Alphabet[i]) {
+			return nil, &ValidationError{Field: "alphabet", Value: opts.Alphabet, Reason: "must only contain characters allowed in a local part"}
+		}
+	}
+	if !strings.Contains(opts.Template, "{rand}") {
+		return nil, &ValidationError{Field: "template", Value: opts.Template, Reason: "must contain {rand}"}
+	}
+
+	seed := opts.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	g := &Generator{
+		opts: opts,
+		now:  time.Now,
+		rnd:  rand.New(rand.NewSource(seed)), //nolint:gosec // Addresses don't need a cryptographic source.
+	}
+	if reason := checkLocal(g.Generate()); reason != "" {
+		return nil, &ValidationError{Field: "template", Value: opts.Template, Reason: reason}
+	}
+	// Start over, so the validation above doesn't consume the seeded sequence.
+	g.rnd = rand.New(rand.NewSource(seed)) //nolint:gosec // See above.
+	return g, nil
+}
+
+// Generate returns a new local part.
+func (g *Generator) Generate() string {
+	r := strings.NewReplacer(
+		"{prefix}", g.opts.Prefix,
+		"{suffix}", g.opts.Suffix,
+		"{date}", g.now().UTC().Format("20060102"),
+		"{rand}", g.random(),
+	)
+	return strings.Trim(r.Replace(g.opts.Template), "-._")
+}
+
+// random returns the random part.
+func (g *Generator) random() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	b := make([]byte, g.opts.Length)
+	for i := range b {
+		b[i] = g.opts.Alphabet[g.rnd.Intn(len(g.opts.Alphabet))]
+	}
+	return string(b)
+}
+
+// CreateEmailWithGenerator creates an email address with a local part from the generator
+// and options.Domain. If the address already exists, it retries with a new local part
+// up to 5 times. options.Email is ignored.
+func (c *Client) CreateEmailWithGenerator(ctx context.Context, gen *Generator, options CreateEmailOptions) (CreateEmailResponse, *Response, error) {
+	if options.Domain == "" {
+		return CreateEmailResponse{}, nil, &ValidationError{Field: "domain", Value: options.Domain, Reason: "must not be empty"}
+	}
+
+	var err error
+	for attempt := 0; attempt < maxGeneratorAttempts; attempt++ {
+		options.Email = gen.Generate() + "@" + options.Domain
+
+		var result CreateEmailResponse
+		var r *Response
+		result, r, err = c.CreateEmail(ctx, options)
+		if !isAlreadyExists(err) {
+			return result, r, err
+		}
+	}
+	return CreateEmailResponse{}, nil, fmt.Errorf("tempmail: no free address after %d attempts: %w", maxGeneratorAttempts, err)
+}
+
+// isAlreadyExists reports whether the error means the email address is taken.
+// The API documentation doesn't specify how a taken address is reported, so this assumes
+// 409 Conflict, the HTTP status for the case. Other errors are returned to the caller.
+func isAlreadyExists(err error) bool {
+	var httpErr *HTTPError
+	return errors.As(err, &httpErr) && httpErr.Response != nil && httpErr.Response.StatusCode == http.StatusConflict
+}
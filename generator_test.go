@@ -0,0 +1,157 @@
+package tempmail
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewGenerator(t *testing.T) {
+	t.Run("defaults", func(t *testing.T) {
+		g, err := NewGenerator(GeneratorOptions{})
+		require.NoError(t, err)
+		assert.Regexp(t, regexp.MustCompile(`^[a-z0-9]{10}$`), g.Generate())
+	})
+
+	t.Run("invalid alphabet", func(t *testing.T) {
+		_, err := NewGenerator(GeneratorOptions{Alphabet: "ab c"})
+		var validationErr *ValidationError
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "alphabet", validationErr.Field)
+	})
+
+	t.Run("template without rand", func(t *testing.T) {
+		_, err := NewGenerator(GeneratorOptions{Template: "{prefix}"})
+		assert.EqualError(t, err, `tempmail: invalid template "{prefix}": must contain {rand}`)
+	})
+
+	t.Run("invalid local part", func(t *testing.T) {
+		_, err := NewGenerator(GeneratorOptions{Prefix: "ci build"})
+		assert.EqualError(t, err, `tempmail: invalid template "{prefix}-{rand}-{suffix}": local part contains an invalid character`)
+	})
+}
+
+func TestGenerator_Generate(t *testing.T) {
+	t.Run("template", func(t *testing.T) {
+		g, err := NewGenerator(GeneratorOptions{
+			Prefix:   "ci-42",
+			Alphabet: "ab",
+			Length:   6,
+			Template: "{prefix}-{date}-{rand}",
+		})
+		require.NoError(t, err)
+		g.now = func() time.Time { return time.Date(2025, 1, 31, 23, 0, 0, 0, time.FixedZone("", -3600)) }
+		assert.Regexp(t, regexp.MustCompile(`^ci-42-20250201-[ab]{6}$`), g.Generate())
+	})
+
+	t.Run("empty placeholders are trimmed", func(t *testing.T) {
+		g, err := NewGenerator(GeneratorOptions{Suffix: "x"})
+		require.NoError(t, err)
+		assert.Regexp(t, regexp.MustCompile(`^[a-z0-9]{10}-x$`), g.Generate())
+	})
+
+	t.Run("seed is reproducible", func(t *testing.T) {
+		g1, err := NewGenerator(GeneratorOptions{Seed: 42})
+		require.NoError(t, err)
+		g2, err := NewGenerator(GeneratorOptions{Seed: 42})
+		require.NoError(t, err)
+		for i := 0; i < 3; i++ {
+			assert.Equal(t, g1.Generate(), g2.Generate())
+		}
+	})
+}
+
+func TestClient_CreateEmailWithGenerator(t *testing.T) {
+	conflict := func(*http.Request) (*http.Response, error) {
+		return newTestResponse(http.StatusConflict, readFile(t, "testdata/error_response.json")), nil
+	}
+
+	t.Run("retries taken addresses", func(t *testing.T) {
+		var emails []string
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(isRequest(http.MethodPost, "/v1/emails")).Run(func(req *http.Request) {
+			var body createEmailRequest
+			b, err := io.ReadAll(req.Body)
+			require.NoError(t, err)
+			require.NoError(t, json.Unmarshal(b, &body))
+			emails = append(emails, body.Email)
+		}).RunAndReturn(conflict).Once()
+		mDoer.EXPECT().Do(isRequest(http.MethodPost, "/v1/emails")).Run(func(req *http.Request) {
+			var body createEmailRequest
+			b, err := io.ReadAll(req.Body)
+			require.NoError(t, err)
+			require.NoError(t, json.Unmarshal(b, &body))
+			emails = append(emails, body.Email)
+		}).Return(newTestResponse(http.StatusOK, readFile(t, "testdata/create_email.json")), nil).Once()
+
+		g, err := NewGenerator(GeneratorOptions{Prefix: "ci", Seed: 1})
+		require.NoError(t, err)
+		c := newClient()
+		c.doer = mDoer
+		result, _, err := c.CreateEmailWithGenerator(context.Background(), g, CreateEmailOptions{Domain: "example.com"})
+		require.NoError(t, err)
+		assert.Equal(t, "test@example.com", result.Email)
+
+		require.Len(t, emails, 2)
+		assert.NotEqual(t, emails[0], emails[1])
+		for _, email := range emails {
+			assert.Regexp(t, regexp.MustCompile(`^ci-[a-z0-9]{10}@example\.com$`), email)
+		}
+	})
+
+	t.Run("gives up", func(t *testing.T) {
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(mock.Anything).RunAndReturn(conflict).Times(maxGeneratorAttempts)
+
+		g, err := NewGenerator(GeneratorOptions{})
+		require.NoError(t, err)
+		c := newClient()
+		c.doer = mDoer
+		_, _, err = c.CreateEmailWithGenerator(context.Background(), g, CreateEmailOptions{Domain: "example.com"})
+		var httpErr *HTTPError
+		require.ErrorAs(t, err, &httpErr)
+		assert.ErrorContains(t, err, "tempmail: no free address after 5 attempts")
+	})
+
+	t.Run("other errors are returned", func(t *testing.T) {
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(mock.Anything).Return(nil, assert.AnError).Once()
+
+		g, err := NewGenerator(GeneratorOptions{})
+		require.NoError(t, err)
+		c := newClient()
+		c.doer = mDoer
+		_, _, err = c.CreateEmailWithGenerator(context.Background(), g, CreateEmailOptions{Domain: "example.com"})
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+
+	t.Run("only conflicts are retried", func(t *testing.T) {
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(mock.Anything).
+			Return(newTestResponse(http.StatusBadRequest, []byte(`{"error":{"type":"request_error","code":"domain_not_exists"}}`)), nil).Once()
+
+		g, err := NewGenerator(GeneratorOptions{})
+		require.NoError(t, err)
+		c := newClient()
+		c.doer = mDoer
+		_, _, err = c.CreateEmailWithGenerator(context.Background(), g, CreateEmailOptions{Domain: "example.com"})
+		var httpErr *HTTPError
+		require.ErrorAs(t, err, &httpErr)
+		assert.Equal(t, http.StatusBadRequest, httpErr.Response.StatusCode)
+	})
+
+	t.Run("domain is required", func(t *testing.T) {
+		g, err := NewGenerator(GeneratorOptions{})
+		require.NoError(t, err)
+		_, _, err = newClient().CreateEmailWithGenerator(context.Background(), g, CreateEmailOptions{})
+		assert.EqualError(t, err, `tempmail: invalid domain "": must not be empty`)
+	})
+}
@@ -2,7 +2,6 @@ package tempmail
 
 import (
 	"context"
-	"fmt"
 	"net/http"
 )
 
@@ -12,14 +11,18 @@ type GetMessageSourceCodeResponse struct {
 	Data string `json:"data"`
 }
 
+// GetMessageSourceCode gets the raw source code of a message by its ID.
 func (c *Client) GetMessageSourceCode(ctx context.Context, messageID string) (GetMessageSourceCodeResponse, *Response, error) {
-	req, err := c.newRequest(ctx, http.MethodGet, fmt.Sprintf("/v1/messages/%s/source", messageID), nil)
+	if err := validateID("messageID", messageID); err != nil {
+		return GetMessageSourceCodeResponse{}, nil, err
+	}
+	req, err := c.newRequest(ctx, OperationGetMessageSourceCode, http.MethodGet, buildPath("v1", "messages", messageID, "source"), nil)
 	if err != nil {
 		return GetMessageSourceCodeResponse{}, nil, err
 	}
 
 	var resp GetMessageSourceCodeResponse
-	r, err := c.do(req, &resp)
+	r, err := c.doCached(req, &resp)
 	if err != nil {
 		return GetMessageSourceCodeResponse{}, nil, err
 	}
@@ -0,0 +1,180 @@
+package tempmail
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultHedgeDelay is the default time to wait for a response before sending a hedged request.
+	DefaultHedgeDelay = 500 * time.Millisecond
+	// DefaultHedgeBudget is the default ratio of hedged requests to hedgeable requests.
+	DefaultHedgeBudget = 0.1
+)
+
+// HedgeOptions represents the options of request hedging.
+type HedgeOptions struct {
+	// Delay is the time to wait for a response before sending a second, hedged request.
+	// Defaults to DefaultHedgeDelay.
+	Delay time.Duration
+	// Budget is the maximum ratio of hedged requests to hedgeable requests, between 0 and 1.
+	// Over n hedgeable requests, at most 1 + Budget*n hedged requests are sent.
+	// Defaults to DefaultHedgeBudget.
+	Budget float64
+}
+
+// WithHedging hedges the GET requests of ListEmailMessages, GetMessage and ListDomains.
+// If a request gets no response within Delay, the same request is sent again, the first
+// response is used and the other request is canceled. Every hedged request counts
+// towards the rate limit, so they are limited by Budget.
+func WithHedging(opts HedgeOptions) ClientOption {
+	return func(c *Client) {
+		c.hedge = newHedger(opts)
+	}
+}
+
+// hedger sends hedged requests within a budget.
+type hedger struct {
+	delay  time.Duration
+	budget float64
+
+	mu sync.Mutex
+	// tokens is the available budget. A hedged request takes one token,
+	// and every hedgeable request adds budget tokens, up to one.
+	tokens float64
+}
+
+func newHedger(opts HedgeOptions) *hedger {
+	if opts.Delay <= 0 {
+		opts.Delay = DefaultHedgeDelay
+	}
+	if opts.Budget <= 0 {
+		opts.Budget = DefaultHedgeBudget
+	}
+	return &hedger{
+		delay:  opts.Delay,
+		budget: min(opts.Budget, 1),
+		tokens: 1,
+	}
+}
+
+// hedgeable reports whether the request may be hedged.
+func hedgeable(req *http.Request) bool {
+	if req.Method != http.MethodGet {
+		return false
+	}
+	switch operationFromContext(req.Context()) {
+	case OperationListEmailMessages, OperationGetMessage, OperationListDomains:
+		return true
+	default:
+		return false
+	}
+}
+
+// record adds the budget of a hedgeable request.
+func (h *hedger) record() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.tokens = min(h.tokens+h.budget, 1)
+}
+
+// take reports whether a hedged request is within the budget, and takes it from the budget.
+func (h *hedger) take() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.tokens < 1 {
+		return false
+	}
+	h.tokens--
+	return true
+}
+
+// hedgeResult is the outcome of one of the requests sent by hedger.do.
+type hedgeResult struct {
+	r     *http.Response
+	err   error
+	index int
+}
+
+// do sends the request, and sends it again if there is no response within the delay.
+// It returns the first response and cancels the other request.
+// A transport error is only returned once both requests have failed.
+func (h *hedger) do(d doer, req *http.Request) (*http.Response, error) {
+	h.record()
+
+	results := make(chan hedgeResult, 2)
+	var cancels []context.CancelFunc
+	send := func(req *http.Request) {
+		ctx, cancel := context.WithCancel(req.Context())
+		cancels = append(cancels, cancel)
+		index := len(cancels) - 1
+		go func() {
+			r, err := d.Do(req.WithContext(ctx))
+			results <- hedgeResult{r: r, err: err, index: index}
+		}()
+	}
+	send(req)
+	pending := 1
+
+	timer := time.NewTimer(h.delay)
+	defer timer.Stop()
+	hedge := timer.C
+
+	for {
+		select {
+		case <-hedge:
+			hedge = nil
+			if !h.take() {
+				continue
+			}
+			clone, err := cloneRequest(req)
+			if err != nil {
+				continue
+			}
+			send(clone)
+			pending++
+		case res := <-results:
+			pending--
+			if res.err != nil && pending > 0 {
+				continue
+			}
+			for i, cancel := range cancels {
+				if i != res.index {
+					cancel()
+				}
+			}
+			go drain(results, pending)
+			if res.err != nil {
+				cancels[res.index]()
+				return nil, res.err
+			}
+			res.r.Body = &cancelBody{ReadCloser: res.r.Body, cancel: cancels[res.index]}
+			return res.r, nil
+		}
+	}
+}
+
+// drain closes the responses of the n canceled requests.
+func drain(results <-chan hedgeResult, n int) {
+	for ; n > 0; n-- {
+		if res := <-results; res.r != nil {
+			res.r.Body.Close()
+		}
+	}
+}
+
+// cancelBody cancels the context of its request once it is closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close closes the body and cancels the request context.
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -0,0 +1,131 @@
+package tempmail
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_hedging(t *testing.T) {
+	t.Run("slow response", func(t *testing.T) {
+		canceled := make(chan struct{})
+		var calls atomic.Int32
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(isRequest(http.MethodGet, "/v1/domains")).RunAndReturn(func(req *http.Request) (*http.Response, error) {
+			if calls.Add(1) == 1 {
+				<-req.Context().Done()
+				close(canceled)
+				return nil, req.Context().Err()
+			}
+			return newTestResponse(http.StatusOK, readFile(t, "testdata/list_domains.json")), nil
+		}).Twice()
+
+		c := NewClient("API_KEY", nil, WithHedging(HedgeOptions{Delay: time.Millisecond}))
+		c.doer = mDoer
+		domains, _, err := c.ListDomains(context.Background())
+		require.NoError(t, err)
+		assert.NotEmpty(t, domains)
+
+		select {
+		case <-canceled:
+		case <-time.After(time.Second):
+			t.Fatal("slow request was not canceled")
+		}
+	})
+
+	t.Run("fast response", func(t *testing.T) {
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(mock.Anything).Return(newTestResponse(http.StatusOK, readFile(t, "testdata/list_domains.json")), nil).Once()
+
+		c := NewClient("API_KEY", nil, WithHedging(HedgeOptions{Delay: time.Hour}))
+		c.doer = mDoer
+		_, _, err := c.ListDomains(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("first request fails", func(t *testing.T) {
+		hedged := make(chan struct{})
+		var calls atomic.Int32
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(mock.Anything).RunAndReturn(func(req *http.Request) (*http.Response, error) {
+			if calls.Add(1) == 1 {
+				<-hedged
+				return nil, assert.AnError
+			}
+			close(hedged)
+			return newTestResponse(http.StatusOK, readFile(t, "testdata/get_message.json")), nil
+		}).Twice()
+
+		c := NewClient("API_KEY", nil, WithHedging(HedgeOptions{Delay: time.Millisecond}))
+		c.doer = mDoer
+		message, _, err := c.GetMessage(context.Background(), testMessageID)
+		require.NoError(t, err)
+		assert.Equal(t, testMessageID, message.ID)
+	})
+
+	t.Run("both requests fail", func(t *testing.T) {
+		var calls atomic.Int32
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(mock.Anything).RunAndReturn(func(req *http.Request) (*http.Response, error) {
+			if calls.Add(1) == 1 {
+				time.Sleep(20 * time.Millisecond)
+			}
+			return nil, assert.AnError
+		}).Twice()
+
+		c := NewClient("API_KEY", nil, WithHedging(HedgeOptions{Delay: time.Millisecond}))
+		c.doer = mDoer
+		_, _, err := c.ListDomains(context.Background())
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+
+	t.Run("not hedgeable", func(t *testing.T) {
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(mock.Anything).RunAndReturn(func(req *http.Request) (*http.Response, error) {
+			time.Sleep(20 * time.Millisecond)
+			return newTestResponse(http.StatusOK, nil), nil
+		}).Once()
+
+		c := NewClient("API_KEY", nil, WithHedging(HedgeOptions{Delay: time.Millisecond}))
+		c.doer = mDoer
+		_, err := c.DeleteMessage(context.Background(), testMessageID)
+		require.NoError(t, err)
+	})
+}
+
+func TestHedger_budget(t *testing.T) {
+	h := newHedger(HedgeOptions{Budget: 0.5})
+	assert.True(t, h.take())
+	assert.False(t, h.take())
+
+	h.record()
+	assert.False(t, h.take())
+	h.record()
+	assert.True(t, h.take())
+
+	// The budget doesn't accumulate beyond one hedged request.
+	for i := 0; i < 10; i++ {
+		h.record()
+	}
+	assert.True(t, h.take())
+	assert.False(t, h.take())
+}
+
+func TestHedgeable(t *testing.T) {
+	newReq := func(op Operation, method string) *http.Request {
+		req, err := newClient().newRequest(context.Background(), op, method, "/", nil)
+		require.NoError(t, err)
+		return req
+	}
+	assert.True(t, hedgeable(newReq(OperationListEmailMessages, http.MethodGet)))
+	assert.True(t, hedgeable(newReq(OperationGetMessage, http.MethodGet)))
+	assert.True(t, hedgeable(newReq(OperationListDomains, http.MethodGet)))
+	assert.False(t, hedgeable(newReq(OperationDownloadAttachment, http.MethodGet)))
+	assert.False(t, hedgeable(newReq(OperationCreateEmail, http.MethodPost)))
+}
@@ -0,0 +1,131 @@
+package tempmail
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// DefaultInboxPollInterval is the default interval between polls in Inbox.Wait.
+const DefaultInboxPollInterval = 2 * time.Second
+
+// Inbox is a handle bound to a single email address created by Client.NewInbox.
+// It is safe for concurrent use.
+type Inbox struct {
+	// PollInterval is the interval between polls in Wait.
+	// If it is not positive, DefaultInboxPollInterval is used.
+	PollInterval time.Duration
+
+	client    *Client
+	email     string
+	expiresAt time.Time
+
+	mu        sync.Mutex
+	delivered map[string]struct{}
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// NewInbox creates an email address and returns an Inbox bound to it.
+// Close the Inbox to delete the address once it is no longer needed.
+func (c *Client) NewInbox(ctx context.Context, options CreateEmailOptions) (*Inbox, error) {
+	resp, _, err := c.CreateEmail(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	return &Inbox{
+		PollInterval: DefaultInboxPollInterval,
+		client:       c,
+		email:        resp.Email,
+		expiresAt:    resp.ExpiresAt,
+		delivered:    make(map[string]struct{}),
+	}, nil
+}
+
+// Email returns the email address of the Inbox.
+func (i *Inbox) Email() string {
+	return i.email
+}
+
+// ExpiresAt returns the time at which the email address expires.
+// It is calculated from the TTL returned by the API.
+func (i *Inbox) ExpiresAt() time.Time {
+	return i.expiresAt
+}
+
+// Messages returns all messages in the Inbox.
+func (i *Inbox) Messages(ctx context.Context) ([]Message, error) {
+	resp, _, err := i.client.ListEmailMessages(ctx, i.email)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Messages, nil
+}
+
+// Wait polls the Inbox until a message arrives that hasn't been returned by Wait before.
+// It returns the context error if the context is done first.
+func (i *Inbox) Wait(ctx context.Context) (Message, error) {
+	interval := i.PollInterval
+	if interval <= 0 {
+		interval = DefaultInboxPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		messages, err := i.Messages(ctx)
+		if err != nil {
+			return Message{}, err
+		}
+		if m, ok := i.nextUndelivered(messages); ok {
+			return m, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return Message{}, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// nextUndelivered returns the first message not yet returned by Wait and marks it as delivered.
+func (i *Inbox) nextUndelivered(messages []Message) (Message, bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	for _, m := range messages {
+		if _, ok := i.delivered[m.ID]; ok {
+			continue
+		}
+		i.delivered[m.ID] = struct{}{}
+		return m, true
+	}
+	return Message{}, false
+}
+
+// Get gets a message by its ID.
+func (i *Inbox) Get(ctx context.Context, messageID string) (Message, error) {
+	resp, _, err := i.client.GetMessage(ctx, messageID)
+	return resp, err
+}
+
+// Delete deletes a message by its ID.
+func (i *Inbox) Delete(ctx context.Context, messageID string) error {
+	_, err := i.client.DeleteMessage(ctx, messageID)
+	return err
+}
+
+// Close deletes the email address along with all its messages.
+// An already expired address is not an error.
+// Subsequent calls return the result of the first one.
+func (i *Inbox) Close() error {
+	i.closeOnce.Do(func() {
+		_, err := i.client.DeleteEmail(context.Background(), i.email)
+		if !errors.Is(err, ErrEmailExpired) {
+			i.closeErr = err
+		}
+	})
+	return i.closeErr
+}
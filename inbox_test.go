@@ -0,0 +1,138 @@
+package tempmail
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestInbox(t *testing.T, mDoer *mockDoer) *Inbox {
+	mDoer.EXPECT().Do(isRequest(http.MethodPost, "/v1/emails")).
+		Return(newTestResponse(http.StatusOK, readFile(t, "testdata/create_email.json")), nil).Once()
+
+	c := newClient()
+	c.doer = mDoer
+	inbox, err := c.NewInbox(context.Background(), CreateEmailOptions{})
+	require.NoError(t, err)
+	return inbox
+}
+
+func TestClient_NewInbox(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		before := time.Now()
+		inbox := newTestInbox(t, newMockDoer(t))
+		assert.Equal(t, "test@example.com", inbox.Email())
+		assert.Equal(t, DefaultInboxPollInterval, inbox.PollInterval)
+		assert.WithinRange(t, inbox.ExpiresAt(), before.Add(time.Hour), time.Now().Add(time.Hour))
+	})
+
+	t.Run("error from CreateEmail", func(t *testing.T) {
+		c := newClient()
+		_, err := c.NewInbox(nil, CreateEmailOptions{})
+		assert.EqualError(t, err, "net/http: nil Context")
+	})
+}
+
+func TestInbox_Messages(t *testing.T) {
+	mDoer := newMockDoer(t)
+	inbox := newTestInbox(t, mDoer)
+	mDoer.EXPECT().Do(isRequest(http.MethodGet, "/v1/emails/test@example.com/messages")).
+		Return(newTestResponse(http.StatusOK, readFile(t, "testdata/list_email_messages.json")), nil)
+
+	messages, err := inbox.Messages(context.Background())
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, "01JE97FT950QRPDYGDXJ4R43QR", messages[0].ID)
+}
+
+func TestInbox_Wait(t *testing.T) {
+	t.Run("waits for new message", func(t *testing.T) {
+		mDoer := newMockDoer(t)
+		inbox := newTestInbox(t, mDoer)
+		inbox.PollInterval = time.Millisecond
+		path := "/v1/emails/test@example.com/messages"
+		mDoer.EXPECT().Do(isRequest(http.MethodGet, path)).
+			Return(newTestResponse(http.StatusOK, []byte(`{"messages":[]}`)), nil).Once()
+		mDoer.EXPECT().Do(isRequest(http.MethodGet, path)).
+			RunAndReturn(func(*http.Request) (*http.Response, error) {
+				return newTestResponse(http.StatusOK, readFile(t, "testdata/list_email_messages.json")), nil
+			})
+
+		m, err := inbox.Wait(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "01JE97FT950QRPDYGDXJ4R43QR", m.ID)
+
+		// The same message is not returned twice.
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		_, err = inbox.Wait(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("non-positive poll interval", func(t *testing.T) {
+		mDoer := newMockDoer(t)
+		inbox := newTestInbox(t, mDoer)
+		inbox.PollInterval = 0
+		mDoer.EXPECT().Do(isRequest(http.MethodGet, "/v1/emails/test@example.com/messages")).
+			Return(newTestResponse(http.StatusOK, []byte(`{"messages":[]}`)), nil).Once()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		_, err := inbox.Wait(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("error from ListEmailMessages", func(t *testing.T) {
+		mDoer := newMockDoer(t)
+		inbox := newTestInbox(t, mDoer)
+		mDoer.EXPECT().Do(isRequest(http.MethodGet, "/v1/emails/test@example.com/messages")).Return(nil, assert.AnError)
+
+		_, err := inbox.Wait(context.Background())
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
+
+func TestInbox_Get(t *testing.T) {
+	mDoer := newMockDoer(t)
+	inbox := newTestInbox(t, mDoer)
+	mDoer.EXPECT().Do(isRequest(http.MethodGet, "/v1/messages/01JE97FT950QRPDYGDXJ4R43QR")).
+		Return(newTestResponse(http.StatusOK, readFile(t, "testdata/get_message.json")), nil)
+
+	m, err := inbox.Get(context.Background(), "01JE97FT950QRPDYGDXJ4R43QR")
+	require.NoError(t, err)
+	assert.Equal(t, "Test Message", m.Subject)
+}
+
+func TestInbox_Delete(t *testing.T) {
+	mDoer := newMockDoer(t)
+	inbox := newTestInbox(t, mDoer)
+	mDoer.EXPECT().Do(isRequest(http.MethodDelete, "/v1/messages/01JE97FT950QRPDYGDXJ4R43QR")).
+		Return(newTestResponse(http.StatusOK, []byte("{}")), nil)
+
+	require.NoError(t, inbox.Delete(context.Background(), "01JE97FT950QRPDYGDXJ4R43QR"))
+}
+
+func TestInbox_Close(t *testing.T) {
+	t.Run("deletes address once", func(t *testing.T) {
+		mDoer := newMockDoer(t)
+		inbox := newTestInbox(t, mDoer)
+		mDoer.EXPECT().Do(isRequest(http.MethodDelete, "/v1/emails/test@example.com")).
+			Return(newTestResponse(http.StatusOK, []byte("{}")), nil).Once()
+
+		require.NoError(t, inbox.Close())
+		require.NoError(t, inbox.Close())
+	})
+
+	t.Run("error from DeleteEmail", func(t *testing.T) {
+		mDoer := newMockDoer(t)
+		inbox := newTestInbox(t, mDoer)
+		mDoer.EXPECT().Do(isRequest(http.MethodDelete, "/v1/emails/test@example.com")).Return(nil, assert.AnError).Once()
+
+		assert.ErrorIs(t, inbox.Close(), assert.AnError)
+		assert.ErrorIs(t, inbox.Close(), assert.AnError)
+	})
+}
@@ -0,0 +1,111 @@
+package tempmail
+
+import (
+	"context"
+	"time"
+)
+
+// Attribute keys used by CallInfo.Attributes.
+// They follow OpenTelemetry semantic conventions where one exists.
+const (
+	AttributeOperation  = "tempmail.operation"
+	AttributeMethod     = "http.request.method"
+	AttributeStatusCode = "http.response.status_code"
+	AttributeErrorCode  = "tempmail.error.code"
+	AttributeRetries    = "tempmail.retries"
+)
+
+// Tracer starts a span for every API call made by the Client.
+// It has the same shape as an OpenTelemetry tracer, so binding it to the OTel SDK
+// only requires mapping CallInfo.Attributes to span attributes.
+type Tracer interface {
+	// StartCall is called before the request is sent.
+	// The returned context is used for the outgoing request.
+	StartCall(ctx context.Context, op Operation) (context.Context, Span)
+}
+
+// Span is an in-flight API call started by Tracer.
+type Span interface {
+	// End is called once the call has finished, successfully or not.
+	End(info CallInfo)
+}
+
+// Metrics records per-operation counters, latency histograms and the rate limit gauge.
+type Metrics interface {
+	// RecordCall is called once for every finished API call.
+	RecordCall(ctx context.Context, info CallInfo)
+	// RecordRateRemaining is called with Rate.Remaining of every response that reports rate limits.
+	RecordRateRemaining(ctx context.Context, remaining int)
+}
+
+// CallInfo describes a finished API call.
+type CallInfo struct {
+	// Operation is the Client method that made the call.
+	Operation Operation
+	// Method is the HTTP method of the request.
+	Method string
+	// StatusCode is the HTTP status code of the response, or 0 if no response was received.
+	StatusCode int
+	// ErrorCode is HTTPErrorError.Code of the API error, if any.
+	ErrorCode string
+	// Retries is the number of times the request was retried.
+	Retries int
+	// Duration is the time spent on the call.
+	Duration time.Duration
+	// Rate is the rate limit reported by the response.
+	Rate Rate
+	// Err is the error returned to the caller.
+	Err error
+}
+
+// Attribute is a key-value pair describing a call.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Attributes returns the semantic attributes of the call.
+// Status and error code are only included when present.
+func (i CallInfo) Attributes() []Attribute {
+	attrs := []Attribute{
+		{Key: AttributeOperation, Value: string(i.Operation)},
+		{Key: AttributeMethod, Value: i.Method},
+		{Key: AttributeRetries, Value: i.Retries},
+	}
+	if i.StatusCode != 0 {
+		attrs = append(attrs, Attribute{Key: AttributeStatusCode, Value: i.StatusCode})
+	}
+	if i.ErrorCode != "" {
+		attrs = append(attrs, Attribute{Key: AttributeErrorCode, Value: i.ErrorCode})
+	}
+	return attrs
+}
+
+// WithTracer sets the Tracer that receives a span for every API call.
+func WithTracer(t Tracer) ClientOption {
+	return func(c *Client) {
+		c.tracer = t
+	}
+}
+
+// WithMetrics sets the Metrics that record every API call.
+func WithMetrics(m Metrics) ClientOption {
+	return func(c *Client) {
+		c.metrics = m
+	}
+}
+
+// observe reports the finished call to the configured Tracer and Metrics.
+func (c *Client) observe(ctx context.Context, span Span, info CallInfo) {
+	if span != nil {
+		span.End(info)
+	}
+	if c.metrics == nil {
+		return
+	}
+	c.metrics.RecordCall(ctx, info)
+	// Limit is zero when the response didn't carry rate limit headers.
+	if info.Rate.Limit != 0 {
+		c.metrics.RecordRateRemaining(ctx, info.Rate.Remaining)
+	}
+}
@@ -0,0 +1,150 @@
+package tempmail
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type spanKey struct{}
+
+// testTracer records finished spans.
+type testTracer struct {
+	mu    sync.Mutex
+	ops   []Operation
+	infos []CallInfo
+}
+
+func (t *testTracer) StartCall(ctx context.Context, op Operation) (context.Context, Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.ops = append(t.ops, op)
+	return context.WithValue(ctx, spanKey{}, op), t
+}
+
+func (t *testTracer) End(info CallInfo) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.infos = append(t.infos, info)
+}
+
+// testMetrics records calls and rate gauges.
+type testMetrics struct {
+	mu        sync.Mutex
+	calls     []CallInfo
+	remaining []int
+}
+
+func (m *testMetrics) RecordCall(_ context.Context, info CallInfo) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, info)
+}
+
+func (m *testMetrics) RecordRateRemaining(_ context.Context, remaining int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.remaining = append(m.remaining, remaining)
+}
+
+func TestClient_instrumentation(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		resp := newTestResponse(http.StatusOK, readFile(t, "testdata/list_domains.json"))
+		resp.Header = http.Header{}
+		resp.Header.Set(headerRateLimit, "1000")
+		resp.Header.Set(headerRateRemaining, "999")
+
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(mock.MatchedBy(func(req *http.Request) bool {
+			return req.Context().Value(spanKey{}) == OperationListDomains
+		})).Return(resp, nil)
+
+		tracer := &testTracer{}
+		metrics := &testMetrics{}
+		c := NewClient("API_KEY", nil, WithTracer(tracer), WithMetrics(metrics))
+		c.doer = mDoer
+		_, _, err := c.ListDomains(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, []Operation{OperationListDomains}, tracer.ops)
+		require.Len(t, tracer.infos, 1)
+		info := tracer.infos[0]
+		assert.Equal(t, OperationListDomains, info.Operation)
+		assert.Equal(t, http.MethodGet, info.Method)
+		assert.Equal(t, http.StatusOK, info.StatusCode)
+		assert.Empty(t, info.ErrorCode)
+		assert.NoError(t, info.Err)
+
+		assert.Equal(t, []CallInfo{info}, metrics.calls)
+		assert.Equal(t, []int{999}, metrics.remaining)
+	})
+
+	t.Run("API error", func(t *testing.T) {
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(mock.Anything).Return(newTestResponse(http.StatusNotFound, readFile(t, "testdata/error_response.json")), nil)
+
+		tracer := &testTracer{}
+		metrics := &testMetrics{}
+		c := NewClient("API_KEY", nil, WithTracer(tracer), WithMetrics(metrics))
+		c.doer = mDoer
+		_, _, err := c.DownloadAttachment(context.Background(), "01JE97K1PBYVGKY0PVE3KXSBF9")
+		require.Error(t, err)
+
+		require.Len(t, tracer.infos, 1)
+		info := tracer.infos[0]
+		assert.Equal(t, OperationDownloadAttachment, info.Operation)
+		assert.Equal(t, http.StatusNotFound, info.StatusCode)
+		assert.Equal(t, "not_found", info.ErrorCode)
+		assert.Equal(t, err, info.Err)
+		assert.Empty(t, metrics.remaining)
+	})
+
+	t.Run("transport error", func(t *testing.T) {
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(mock.Anything).Return(nil, assert.AnError)
+
+		metrics := &testMetrics{}
+		c := NewClient("API_KEY", nil, WithMetrics(metrics))
+		c.doer = mDoer
+		_, err := c.DeleteEmail(context.Background(), "test@example.com")
+		require.Error(t, err)
+
+		require.Len(t, metrics.calls, 1)
+		assert.Equal(t, OperationDeleteEmail, metrics.calls[0].Operation)
+		assert.Zero(t, metrics.calls[0].StatusCode)
+		assert.ErrorIs(t, metrics.calls[0].Err, assert.AnError)
+	})
+}
+
+func TestCallInfo_Attributes(t *testing.T) {
+	t.Run("all attributes", func(t *testing.T) {
+		info := CallInfo{
+			Operation:  OperationGetMessage,
+			Method:     http.MethodGet,
+			StatusCode: http.StatusNotFound,
+			ErrorCode:  "not_found",
+			Retries:    2,
+		}
+		assert.Equal(t, []Attribute{
+			{Key: AttributeOperation, Value: "GetMessage"},
+			{Key: AttributeMethod, Value: "GET"},
+			{Key: AttributeRetries, Value: 2},
+			{Key: AttributeStatusCode, Value: 404},
+			{Key: AttributeErrorCode, Value: "not_found"},
+		}, info.Attributes())
+	})
+
+	t.Run("no response", func(t *testing.T) {
+		info := CallInfo{Operation: OperationRateLimit, Method: http.MethodGet}
+		assert.Equal(t, []Attribute{
+			{Key: AttributeOperation, Value: "RateLimit"},
+			{Key: AttributeMethod, Value: "GET"},
+			{Key: AttributeRetries, Value: 0},
+		}, info.Attributes())
+	})
+}
@@ -0,0 +1,236 @@
+package tempmail
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultKeyBackoff is the time a key that returned 429 without a reset time is not used.
+const defaultKeyBackoff = time.Minute
+
+// KeyUsage represents the usage of one API key of the Client.
+type KeyUsage struct {
+	// Key is the masked API key, showing only its last 4 characters.
+	Key string
+	// Requests is the number of requests sent with the key.
+	Requests int
+	// RateLimited is the number of 429 Too Many Requests responses to the key.
+	RateLimited int
+	// Rate is the rate limit of the latest response to the key.
+	Rate Rate
+	// ExhaustedUntil is the time until which the key is avoided after a 429 response.
+	ExhaustedUntil time.Time
+}
+
+// WithAPIKeys adds API keys to the Client, for example of several teams.
+// Every request uses the available key with the most remaining calls. A key that returns
+// 429 Too Many Requests is avoided until its rate limit resets, and the request is sent again
+// with another key. The key passed to NewClient, if any, is part of the pool.
+func WithAPIKeys(keys ...string) ClientOption {
+	return func(c *Client) {
+		if c.keys == nil {
+			c.keys = newKeyPool(c.apiKey)
+		}
+		c.keys.add(keys...)
+	}
+}
+
+// KeyUsage returns the usage of every API key of the Client, in the order they were added.
+// It returns nil if WithAPIKeys is not used.
+func (c *Client) KeyUsage() []KeyUsage {
+	if c.keys == nil {
+		return nil
+	}
+	return c.keys.usage()
+}
+
+// apiKeyFor returns the API key for a new request.
+func (c *Client) apiKeyFor(ctx context.Context) (string, error) {
+	switch {
+	case c.keys != nil:
+		return c.keys.choose(c.now()), nil
+	case c.credentials != nil:
+		return c.credentials.get(ctx)
+	default:
+		return c.apiKey, nil
+	}
+}
+
+// rawDoWithKeys sends the request and, if the API answers 429 Too Many Requests,
+// sends it again with every other available key of the pool.
+func (c *Client) rawDoWithKeys(req *http.Request) (*Response, error) {
+	for {
+		r, err := c.rawDo(req)
+		if c.keys == nil || err != nil {
+			return r, err
+		}
+		now := c.now()
+		key := req.Header.Get(headerAPIKey)
+		c.keys.update(key, r, now)
+		if r.StatusCode != http.StatusTooManyRequests {
+			return r, nil
+		}
+
+		next := c.keys.choose(now)
+		if next == key || c.keys.exhausted(next, now) {
+			return r, nil
+		}
+		retry, err := withAPIKey(req, next)
+		if err != nil {
+			return r, nil
+		}
+		r.Body.Close()
+		req = retry
+	}
+}
+
+// withAPIKey returns a copy of the request with another API key.
+func withAPIKey(req *http.Request, key string) (*http.Request, error) {
+	retry, err := cloneRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	retry.Header.Set(headerAPIKey, key)
+	return retry, nil
+}
+
+// maskKey hides all but the last 4 characters of the key.
+func maskKey(key string) string {
+	if len(key) <= 8 {
+		return strings.Repeat("*", len(key))
+	}
+	return strings.Repeat("*", len(key)-4) + key[len(key)-4:]
+}
+
+// keyPool tracks the usage of several API keys.
+type keyPool struct {
+	mu   sync.Mutex
+	keys []*keyState
+}
+
+// keyState is the usage of one key.
+type keyState struct {
+	key            string
+	requests       int
+	rateLimited    int
+	rate           Rate
+	exhaustedUntil time.Time
+}
+
+func newKeyPool(keys ...string) *keyPool {
+	p := &keyPool{}
+	p.add(keys...)
+	return p
+}
+
+// add adds the keys, ignoring empty and duplicate ones.
+func (p *keyPool) add(keys ...string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, key := range keys {
+		if key != "" && p.find(key) == nil {
+			p.keys = append(p.keys, &keyState{key: key})
+		}
+	}
+}
+
+// find returns the state of the key. p.mu must be held.
+func (p *keyPool) find(key string) *keyState {
+	for _, k := range p.keys {
+		if k.key == key {
+			return k
+		}
+	}
+	return nil
+}
+
+// choose returns the available key with the most remaining calls. Keys without a known
+// rate limit come first. If all keys are exhausted, it returns the one that resets first.
+func (p *keyPool) choose(now time.Time) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var best *keyState
+	for _, k := range p.keys {
+		if best == nil || k.better(best, now) {
+			best = k
+		}
+	}
+	if best == nil {
+		return ""
+	}
+	return best.key
+}
+
+// exhausted reports whether the key is avoided after a 429 response.
+func (p *keyPool) exhausted(key string, now time.Time) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	k := p.find(key)
+	return k != nil && k.exhaustedUntil.After(now)
+}
+
+// update records the response to a request sent with the key.
+func (p *keyPool) update(key string, r *Response, now time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	k := p.find(key)
+	if k == nil {
+		return
+	}
+	k.requests++
+	if r.Rate.Limit != 0 {
+		k.rate = r.Rate
+	}
+	if r.StatusCode == http.StatusTooManyRequests {
+		k.rateLimited++
+		k.exhaustedUntil = now.Add(defaultKeyBackoff)
+		if r.Rate.Reset.After(now) {
+			k.exhaustedUntil = r.Rate.Reset
+		}
+	}
+}
+
+// usage returns the usage of the keys.
+func (p *keyPool) usage() []KeyUsage {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	result := make([]KeyUsage, len(p.keys))
+	for i, k := range p.keys {
+		result[i] = KeyUsage{
+			Key:            maskKey(k.key),
+			Requests:       k.requests,
+			RateLimited:    k.rateLimited,
+			Rate:           k.rate,
+			ExhaustedUntil: k.exhaustedUntil,
+		}
+	}
+	return result
+}
+
+// better reports whether k should be used rather than other.
+func (k *keyState) better(other *keyState, now time.Time) bool {
+	kExhausted, otherExhausted := k.exhaustedUntil.After(now), other.exhaustedUntil.After(now)
+	if kExhausted != otherExhausted {
+		return !kExhausted
+	}
+	if kExhausted {
+		return k.exhaustedUntil.Before(other.exhaustedUntil)
+	}
+	return k.remaining(now) > other.remaining(now)
+}
+
+// remaining returns the remaining calls of the key. It is unlimited if the rate limit
+// is unknown or has been reset since the latest response.
+func (k *keyState) remaining(now time.Time) int {
+	if k.rate.Limit == 0 || (!k.rate.Reset.IsZero() && !k.rate.Reset.After(now)) {
+		return math.MaxInt
+	}
+	return k.rate.Remaining
+}
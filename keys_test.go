@@ -0,0 +1,132 @@
+package tempmail
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// hasAPIKey matches a request sent with the API key.
+func hasAPIKey(key string) interface{} {
+	return mock.MatchedBy(func(req *http.Request) bool {
+		return req.Header.Get(headerAPIKey) == key
+	})
+}
+
+// newRateResponse creates a response with rate limit headers.
+func newRateResponse(statusCode int, body []byte, limit, remaining int, reset time.Time) *http.Response {
+	r := newTestResponse(statusCode, body)
+	r.Header = make(http.Header)
+	r.Header.Set(headerRateLimit, strconv.Itoa(limit))
+	r.Header.Set(headerRateRemaining, strconv.Itoa(remaining))
+	r.Header.Set(headerRateReset, strconv.FormatInt(reset.Unix(), 10))
+	return r
+}
+
+func TestClient_apiKeys(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	reset := now.Add(time.Hour)
+
+	t.Run("most remaining calls", func(t *testing.T) {
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(hasAPIKey("key-a")).Return(newRateResponse(http.StatusOK, []byte(`{"domains":[]}`), 100, 10, reset), nil).Once()
+		mDoer.EXPECT().Do(hasAPIKey("key-b")).RunAndReturn(func(*http.Request) (*http.Response, error) {
+			return newRateResponse(http.StatusOK, []byte(`{"domains":[]}`), 100, 50, reset), nil
+		}).Twice()
+
+		c := NewClient("key-a", nil, WithAPIKeys("key-b"))
+		c.now = func() time.Time { return now }
+		c.doer = mDoer
+		// key-a is used first, then key-b while its rate limit is unknown, then key-b because it has more calls left.
+		for i := 0; i < 3; i++ {
+			_, _, err := c.ListDomains(context.Background())
+			require.NoError(t, err)
+		}
+	})
+
+	t.Run("rotation on 429", func(t *testing.T) {
+		var bodies []string
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(hasAPIKey("key-a")).RunAndReturn(func(req *http.Request) (*http.Response, error) {
+			b, _ := io.ReadAll(req.Body)
+			bodies = append(bodies, string(b))
+			return newRateResponse(http.StatusTooManyRequests, readFile(t, "testdata/error_response.json"), 100, 0, reset), nil
+		}).Once()
+		mDoer.EXPECT().Do(hasAPIKey("key-b")).RunAndReturn(func(req *http.Request) (*http.Response, error) {
+			b, _ := io.ReadAll(req.Body)
+			bodies = append(bodies, string(b))
+			return newTestResponse(http.StatusOK, readFile(t, "testdata/create_email.json")), nil
+		}).Twice()
+
+		c := NewClient("", nil, WithAPIKeys("key-a", "key-b"))
+		c.now = func() time.Time { return now }
+		c.doer = mDoer
+
+		_, _, err := c.CreateEmail(context.Background(), CreateEmailOptions{Domain: "example.com"})
+		require.NoError(t, err)
+		require.Len(t, bodies, 2)
+		assert.Equal(t, bodies[0], bodies[1])
+
+		// key-a is avoided until its rate limit resets.
+		_, _, err = c.CreateEmail(context.Background(), CreateEmailOptions{Domain: "example.com"})
+		require.NoError(t, err)
+
+		usage := c.KeyUsage()
+		require.Len(t, usage, 2)
+		assert.Equal(t, KeyUsage{
+			Key:            "*****",
+			Requests:       1,
+			RateLimited:    1,
+			Rate:           Rate{Limit: 100, Remaining: 0, Reset: time.Unix(reset.Unix(), 0)},
+			ExhaustedUntil: time.Unix(reset.Unix(), 0),
+		}, usage[0])
+		assert.Equal(t, 2, usage[1].Requests)
+
+		now = reset
+		key, err := c.apiKeyFor(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "key-a", key)
+	})
+
+	t.Run("all keys exhausted", func(t *testing.T) {
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(mock.Anything).RunAndReturn(func(*http.Request) (*http.Response, error) {
+			return newRateResponse(http.StatusTooManyRequests, readFile(t, "testdata/error_response.json"), 100, 0, reset), nil
+		}).Twice()
+
+		c := NewClient("key-a", nil, WithAPIKeys("key-b"))
+		c.now = func() time.Time { return time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC) }
+		c.doer = mDoer
+
+		_, _, err := c.ListDomains(context.Background())
+		var httpErr *HTTPError
+		require.ErrorAs(t, err, &httpErr)
+		assert.Equal(t, http.StatusTooManyRequests, httpErr.Response.StatusCode)
+	})
+
+	t.Run("without WithAPIKeys", func(t *testing.T) {
+		c := newClient()
+		assert.Nil(t, c.KeyUsage())
+		key, err := c.apiKeyFor(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "API_KEY", key)
+	})
+}
+
+func TestKeyPool_add(t *testing.T) {
+	p := newKeyPool("a", "", "b", "a")
+	assert.Len(t, p.usage(), 2)
+}
+
+func TestMaskKey(t *testing.T) {
+	assert.Equal(t, "", maskKey(""))
+	assert.Equal(t, "********", maskKey("short-ke"))
+	assert.Equal(t, "************cdef", maskKey("0123456789abcdef"))
+}
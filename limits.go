@@ -0,0 +1,179 @@
+package tempmail
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	headerAcceptEncoding  = "Accept-Encoding"
+	headerContentEncoding = "Content-Encoding"
+	headerContentLength   = "Content-Length"
+)
+
+// ErrResponseTooLarge is matched by the *ResponseTooLargeError returned when
+// a response body exceeds its limit.
+var ErrResponseTooLarge = errors.New("tempmail: response too large")
+
+// DefaultResponseLimits are the ResponseLimits used by a Client created without WithResponseLimits.
+var DefaultResponseLimits = ResponseLimits{
+	Metadata:   1 << 20,
+	Message:    32 << 20,
+	Attachment: 64 << 20,
+	Mutation:   1 << 20,
+}
+
+// ResponseLimits are the maximum sizes in bytes of the response bodies of the operation
+// classes, after decompression. They apply to error responses too.
+// A zero field disables the limit of its class.
+type ResponseLimits struct {
+	// Metadata is the limit of ListDomains and RateLimit.
+	Metadata int64
+	// Message is the limit of ListEmailMessages, GetMessage, GetMessageSourceCode and Do.
+	Message int64
+	// Attachment is the limit of DownloadAttachment.
+	Attachment int64
+	// Mutation is the limit of CreateEmail, DeleteEmail and DeleteMessage.
+	Mutation int64
+}
+
+// WithResponseLimits sets the maximum response body sizes of the operation classes.
+// Use a zero ResponseLimits to disable them.
+func WithResponseLimits(l ResponseLimits) ClientOption {
+	return func(c *Client) {
+		c.limits = l
+	}
+}
+
+// limit returns the response limit of the operation.
+func (l ResponseLimits) limit(op Operation) int64 {
+	switch op.class() {
+	case classMetadata:
+		return l.Metadata
+	case classMessage:
+		return l.Message
+	case classAttachment:
+		return l.Attachment
+	case classMutation:
+		return l.Mutation
+	default:
+		return 0
+	}
+}
+
+// ResponseTooLargeError is returned when a response body exceeds the limit of its operation.
+type ResponseTooLargeError struct {
+	// Operation is the Client method whose response was too large.
+	Operation Operation
+	// Limit is the exceeded limit in bytes.
+	Limit int64
+}
+
+// Error implements the error interface.
+func (e *ResponseTooLargeError) Error() string {
+	return fmt.Sprintf("%s: %s response exceeds %d bytes", ErrResponseTooLarge, e.Operation, e.Limit)
+}
+
+// Is reports whether the target is ErrResponseTooLarge.
+func (e *ResponseTooLargeError) Is(target error) bool {
+	return target == ErrResponseTooLarge
+}
+
+// limitBody decompresses the body of a gzip-encoded response and limits the size of the body.
+// The request asks for gzip itself, so the transport leaves decompression to the Client.
+// Errors are returned when the body is read, so the response is not mistaken for a
+// transport error and retried.
+func (c *Client) limitBody(req *http.Request, r *http.Response) {
+	op := operationFromContext(req.Context())
+	limit := c.limits.limit(op)
+
+	if strings.EqualFold(r.Header.Get(headerContentEncoding), "gzip") {
+		r.Body = &gzipBody{body: r.Body}
+		r.Header.Del(headerContentEncoding)
+		r.Header.Del(headerContentLength)
+		r.ContentLength = -1
+		r.Uncompressed = true
+	}
+	if limit > 0 {
+		r.Body = &limitedBody{
+			body:      r.Body,
+			remaining: limit,
+			// Fail without reading if the body is known to be too large.
+			exceeded: r.ContentLength > limit,
+			err:      &ResponseTooLargeError{Operation: op, Limit: limit},
+		}
+	}
+}
+
+// gzipBody decompresses a response body. The gzip header is read on the first Read.
+type gzipBody struct {
+	body io.ReadCloser
+	zr   *gzip.Reader
+	err  error
+}
+
+// Read implements io.Reader.
+func (b *gzipBody) Read(p []byte) (int, error) {
+	if b.zr == nil && b.err == nil {
+		b.zr, b.err = gzip.NewReader(b.body)
+		if b.err != nil {
+			b.err = fmt.Errorf("tempmail: decompress response: %w", b.err)
+		}
+	}
+	if b.err != nil {
+		return 0, b.err
+	}
+	return b.zr.Read(p)
+}
+
+// Close closes the decompressor and the underlying body.
+func (b *gzipBody) Close() error {
+	var err error
+	if b.zr != nil {
+		err = b.zr.Close()
+	}
+	return errors.Join(err, b.body.Close())
+}
+
+// limitedBody fails reading once more than remaining bytes are read from the body.
+type limitedBody struct {
+	body      io.ReadCloser
+	remaining int64
+	exceeded  bool
+	err       error
+}
+
+// Read implements io.Reader.
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.exceeded {
+		return 0, b.err
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	if b.remaining <= 0 {
+		// Tell a body of exactly the limit from a larger one.
+		var probe [1]byte
+		n, err := b.body.Read(probe[:])
+		if n > 0 {
+			b.exceeded = true
+			return 0, b.err
+		}
+		return 0, err
+	}
+	if int64(len(p)) > b.remaining {
+		p = p[:b.remaining]
+	}
+	n, err := b.body.Read(p)
+	b.remaining -= int64(n)
+	return n, err
+}
+
+// Close implements io.Closer.
+func (b *limitedBody) Close() error {
+	return b.body.Close()
+}
@@ -0,0 +1,134 @@
+package tempmail
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newGzipResponse returns a response with the gzip-compressed body.
+func newGzipResponse(t *testing.T, statusCode int, body []byte) *http.Response {
+	var b bytes.Buffer
+	zw := gzip.NewWriter(&b)
+	_, err := zw.Write(body)
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	r := newTestResponse(statusCode, b.Bytes())
+	r.Header = http.Header{}
+	r.Header.Set(headerContentEncoding, "gzip")
+	r.ContentLength = int64(b.Len())
+	return r
+}
+
+func TestClient_responseLimits(t *testing.T) {
+	domains := readFile(t, "testdata/list_domains.json")
+
+	t.Run("too large", func(t *testing.T) {
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(mock.Anything).Return(newTestResponse(http.StatusOK, domains), nil).Once()
+
+		c := NewClient("API_KEY", nil, WithResponseLimits(ResponseLimits{Metadata: 10}))
+		c.doer = mDoer
+		_, _, err := c.ListDomains(context.Background())
+		require.ErrorIs(t, err, ErrResponseTooLarge)
+		var tooLarge *ResponseTooLargeError
+		require.ErrorAs(t, err, &tooLarge)
+		assert.Equal(t, OperationListDomains, tooLarge.Operation)
+		assert.Equal(t, int64(10), tooLarge.Limit)
+	})
+
+	t.Run("exact limit", func(t *testing.T) {
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(mock.Anything).Return(newTestResponse(http.StatusOK, []byte("attachment")), nil).Once()
+
+		c := NewClient("API_KEY", nil, WithResponseLimits(ResponseLimits{Attachment: int64(len("attachment"))}))
+		c.doer = mDoer
+		data, _, err := c.DownloadAttachment(context.Background(), "attachment")
+		require.NoError(t, err)
+		assert.Equal(t, []byte("attachment"), data)
+	})
+
+	t.Run("content length", func(t *testing.T) {
+		body := &mockReadCloser{}
+		body.EXPECT().Close().Return(nil).Once()
+		r := newTestResponse(http.StatusOK, nil)
+		r.Body = body
+		r.ContentLength = 1 << 30
+
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(mock.Anything).Return(r, nil).Once()
+
+		c := newClient()
+		c.doer = mDoer
+		_, _, err := c.DownloadAttachment(context.Background(), "attachment")
+		assert.ErrorIs(t, err, ErrResponseTooLarge)
+	})
+
+	t.Run("error response", func(t *testing.T) {
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(mock.Anything).Return(newTestResponse(http.StatusBadRequest, readFile(t, "testdata/error_response.json")), nil).Once()
+
+		c := NewClient("API_KEY", nil, WithResponseLimits(ResponseLimits{Mutation: 10}))
+		c.doer = mDoer
+		_, err := c.DeleteMessage(context.Background(), "message")
+		assert.ErrorIs(t, err, ErrResponseTooLarge)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(mock.Anything).Return(newTestResponse(http.StatusOK, domains), nil).Once()
+
+		c := NewClient("API_KEY", nil, WithResponseLimits(ResponseLimits{}))
+		c.doer = mDoer
+		_, _, err := c.ListDomains(context.Background())
+		require.NoError(t, err)
+	})
+}
+
+func TestClient_gzip(t *testing.T) {
+	t.Run("decompress", func(t *testing.T) {
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(mock.MatchedBy(func(req *http.Request) bool {
+			return req.Header.Get(headerAcceptEncoding) == "gzip"
+		})).Return(newGzipResponse(t, http.StatusOK, readFile(t, "testdata/list_domains.json")), nil).Once()
+
+		c := newClient()
+		c.doer = mDoer
+		domains, resp, err := c.ListDomains(context.Background())
+		require.NoError(t, err)
+		assert.NotEmpty(t, domains)
+		assert.True(t, resp.Uncompressed)
+		assert.Empty(t, resp.Header.Get(headerContentEncoding))
+	})
+
+	t.Run("decompression bomb", func(t *testing.T) {
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(mock.Anything).Return(newGzipResponse(t, http.StatusOK, make([]byte, 4<<20)), nil).Once()
+
+		c := NewClient("API_KEY", nil, WithResponseLimits(ResponseLimits{Attachment: 1 << 20}))
+		c.doer = mDoer
+		_, _, err := c.DownloadAttachment(context.Background(), "attachment")
+		assert.ErrorIs(t, err, ErrResponseTooLarge)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		r := newTestResponse(http.StatusOK, []byte("not gzip"))
+		r.Header = http.Header{}
+		r.Header.Set(headerContentEncoding, "gzip")
+
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(mock.Anything).Return(r, nil).Once()
+
+		c := newClient()
+		c.doer = mDoer
+		_, _, err := c.DownloadAttachment(context.Background(), "attachment")
+		assert.ErrorContains(t, err, "decompress response")
+	})
+}
@@ -16,12 +16,12 @@ type ListDomainsDomainResponse struct {
 	Name string `json:"name"`
 	// Type of the domain.
 	// Possible values: "public", "premium", "custom"
-	Type string `json:"type"`
+	Type DomainType `json:"type"`
 }
 
 // ListDomains returns a list of domains available for use.
 func (c *Client) ListDomains(ctx context.Context) (ListDomainsResponse, *Response, error) {
-	req, err := c.newRequest(ctx, http.MethodGet, "/v1/domains", nil)
+	req, err := c.newRequest(ctx, OperationListDomains, http.MethodGet, "/v1/domains", nil)
 	if err != nil {
 		return ListDomainsResponse{}, nil, err
 	}
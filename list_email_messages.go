@@ -2,52 +2,32 @@ package tempmail
 
 import (
 	"context"
-	"fmt"
 	"net/http"
-	"time"
 )
 
 // ListEmailMessagesResponse represents the response to list email messages.
 type ListEmailMessagesResponse struct {
-	Messages []ListEmailMessagesMessageResponse `json:"messages"`
+	Messages []Message `json:"messages"`
 }
 
 // ListEmailMessagesMessageResponse represents an email message.
-type ListEmailMessagesMessageResponse struct {
-	// ID is the unique identifier of the email message.
-	ID string `json:"id"`
-	// From is the email address of the sender.
-	From string `json:"from"`
-	// To is the email address of the recipient.
-	To string `json:"to"`
-	// CC is the email addresses of the CC recipients.
-	CC []string `json:"cc"`
-	// Subject is the subject of the email message.
-	Subject string `json:"subject"`
-	// BodyText is the plain text body of the email message.
-	BodyText string `json:"body_text"`
-	// BodyHTML is the HTML body of the email message.
-	BodyHTML string `json:"body_html"`
-	// CreatedAt is the time when the email message was created.
-	CreatedAt time.Time `json:"created_at"`
-	// Attachments is the list of attachments of the email message.
-	Attachments []ListEmailMessagesAttachmentResponse `json:"attachments"`
-}
+// It is an alias of Message kept for compatibility.
+type ListEmailMessagesMessageResponse = Message
 
 // ListEmailMessagesAttachmentResponse represents an attachment of an email message.
-type ListEmailMessagesAttachmentResponse struct {
-	// ID is the unique identifier of the attachment.
-	ID string `json:"id"`
-	// Name is the name of the attachment.
-	// For example, "image.png".
-	Name string `json:"name"`
-	// Size is the size of the attachment in bytes.
-	Size int `json:"size"`
-}
+// It is an alias of Attachment kept for compatibility.
+type ListEmailMessagesAttachmentResponse = Attachment
 
 // ListEmailMessages returns all messages for the email address.
+// It returns ErrEmailExpired if the email address was created by the Client and has expired.
 func (c *Client) ListEmailMessages(ctx context.Context, email string) (ListEmailMessagesResponse, *Response, error) {
-	req, err := c.newRequest(ctx, http.MethodGet, fmt.Sprintf("/v1/emails/%s/messages", email), nil)
+	if err := validateEmail(email); err != nil {
+		return ListEmailMessagesResponse{}, nil, err
+	}
+	if err := c.known.check(email, c.now()); err != nil {
+		return ListEmailMessagesResponse{}, nil, err
+	}
+	req, err := c.newRequest(ctx, OperationListEmailMessages, http.MethodGet, buildPath("v1", "emails", email, "messages"), nil)
 	if err != nil {
 		return ListEmailMessagesResponse{}, nil, err
 	}
@@ -57,6 +37,7 @@ func (c *Client) ListEmailMessages(ctx context.Context, email string) (ListEmail
 	if err != nil {
 		return ListEmailMessagesResponse{}, nil, err
 	}
+	c.indexMessages(ctx, email, resp.Messages...)
 
 	return resp, r, nil
 }
@@ -0,0 +1,56 @@
+package tempmail
+
+import "time"
+
+// Message represents an email message.
+// It is returned by both ListEmailMessages and GetMessage.
+type Message struct {
+	// ID is the unique identifier of the email message.
+	ID string `json:"id"`
+	// From is the email address of the sender.
+	From string `json:"from"`
+	// To is the email address of the recipient.
+	To string `json:"to"`
+	// CC is the email addresses of the CC recipients.
+	CC []string `json:"cc"`
+	// Subject is the subject of the email message.
+	Subject string `json:"subject"`
+	// BodyText is the plain text body of the email message.
+	BodyText string `json:"body_text"`
+	// BodyHTML is the HTML body of the email message.
+	BodyHTML string `json:"body_html"`
+	// CreatedAt is the time when the email message was created.
+	CreatedAt time.Time `json:"created_at"`
+	// Attachments is the list of attachments of the email message.
+	Attachments []Attachment `json:"attachments"`
+}
+
+// Attachment represents an attachment of an email message.
+type Attachment struct {
+	// ID is the unique identifier of the attachment.
+	ID string `json:"id"`
+	// Name is the name of the attachment.
+	// For example, "image.png".
+	Name string `json:"name"`
+	// Size is the size of the attachment in bytes.
+	Size int `json:"size"`
+}
+
+// HasAttachments reports whether the message has any attachments.
+func (m Message) HasAttachments() bool {
+	return len(m.Attachments) > 0
+}
+
+// TotalAttachmentSize returns the total size of all attachments in bytes.
+func (m Message) TotalAttachmentSize() int {
+	total := 0
+	for _, a := range m.Attachments {
+		total += a.Size
+	}
+	return total
+}
+
+// Age returns the time elapsed since the message was created.
+func (m Message) Age() time.Duration {
+	return time.Since(m.CreatedAt)
+}
@@ -0,0 +1,34 @@
+package tempmail
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessage_HasAttachments(t *testing.T) {
+	assert.False(t, Message{}.HasAttachments())
+	assert.True(t, Message{Attachments: []Attachment{{ID: "1"}}}.HasAttachments())
+}
+
+func TestMessage_TotalAttachmentSize(t *testing.T) {
+	assert.Zero(t, Message{}.TotalAttachmentSize())
+	m := Message{Attachments: []Attachment{{Size: 2048}, {Size: 5120}}}
+	assert.Equal(t, 7168, m.TotalAttachmentSize())
+}
+
+func TestMessage_Age(t *testing.T) {
+	m := Message{CreatedAt: time.Now().Add(-time.Hour)}
+	assert.InDelta(t, time.Hour, m.Age(), float64(time.Minute))
+}
+
+func TestMessage_sharedByListAndGet(t *testing.T) {
+	// Helpers written for Message accept results of both endpoints.
+	var list ListEmailMessagesResponse
+	var get GetMessageResponse
+	sizes := func(m Message) int { return m.TotalAttachmentSize() }
+	assert.Zero(t, sizes(get))
+	list.Messages = append(list.Messages, get)
+	assert.Zero(t, sizes(list.Messages[0]))
+}
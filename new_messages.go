@@ -0,0 +1,82 @@
+package tempmail
+
+import (
+	"context"
+	"sort"
+	"time"
+)
+
+// MessageCursorSkew is how long before MessageCursor.CreatedAt messages are still compared by ID.
+// It tolerates clock skew between the servers that timestamp incoming messages,
+// so a message with an earlier CreatedAt that arrives late is still reported as new.
+const MessageCursorSkew = time.Minute
+
+// MessageCursor marks the messages of an email address that have already been returned by ListNewMessages.
+// The zero value means no message has been seen yet.
+// It can be encoded as JSON to persist it between runs.
+type MessageCursor struct {
+	// CreatedAt is the creation time of the newest seen message.
+	CreatedAt time.Time `json:"created_at"`
+	// IDs are the IDs of the seen messages created within MessageCursorSkew before CreatedAt.
+	IDs []string `json:"ids,omitempty"`
+}
+
+// seen reports whether the message has already been returned for this cursor.
+func (c MessageCursor) seen(m Message) bool {
+	if c.CreatedAt.IsZero() {
+		return false
+	}
+	if m.CreatedAt.Before(c.CreatedAt.Add(-MessageCursorSkew)) {
+		return true
+	}
+	for _, id := range c.IDs {
+		if id == m.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// advance returns the cursor after all the given messages have been seen.
+func (c MessageCursor) advance(messages []Message) MessageCursor {
+	next := MessageCursor{CreatedAt: c.CreatedAt}
+	for _, m := range messages {
+		if m.CreatedAt.After(next.CreatedAt) {
+			next.CreatedAt = m.CreatedAt
+		}
+	}
+	threshold := next.CreatedAt.Add(-MessageCursorSkew)
+	for _, m := range messages {
+		if !m.CreatedAt.Before(threshold) {
+			next.IDs = append(next.IDs, m.ID)
+		}
+	}
+	sort.Strings(next.IDs)
+	return next
+}
+
+// ListNewMessages returns the messages for the email address that were received after the cursor,
+// ordered from oldest to newest, along with the cursor to pass to the next call.
+// Messages with identical CreatedAt are told apart by ID, and messages that arrive late
+// with a CreatedAt up to MessageCursorSkew before the cursor are still returned.
+func (c *Client) ListNewMessages(ctx context.Context, email string, cursor MessageCursor) ([]Message, MessageCursor, *Response, error) {
+	resp, r, err := c.ListEmailMessages(ctx, email)
+	if err != nil {
+		return nil, cursor, nil, err
+	}
+
+	var messages []Message
+	for _, m := range resp.Messages {
+		if !cursor.seen(m) {
+			messages = append(messages, m)
+		}
+	}
+	sort.SliceStable(messages, func(i, j int) bool {
+		if messages[i].CreatedAt.Equal(messages[j].CreatedAt) {
+			return messages[i].ID < messages[j].ID
+		}
+		return messages[i].CreatedAt.Before(messages[j].CreatedAt)
+	})
+
+	return messages, cursor.advance(resp.Messages), r, nil
+}
@@ -0,0 +1,120 @@
+package tempmail
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newMessagesResponse returns a list messages response with the given messages.
+func newMessagesResponse(t *testing.T, messages ...Message) *http.Response {
+	b, err := json.Marshal(ListEmailMessagesResponse{Messages: messages})
+	require.NoError(t, err)
+	return newTestResponse(http.StatusOK, b)
+}
+
+func messageIDs(messages []Message) []string {
+	ids := make([]string, 0, len(messages))
+	for _, m := range messages {
+		ids = append(ids, m.ID)
+	}
+	return ids
+}
+
+func TestClient_ListNewMessages(t *testing.T) {
+	base := time.Date(2025, 1, 31, 22, 0, 0, 0, time.UTC)
+	path := "/v1/emails/user@example.com/messages"
+
+	t.Run("deduplicates across polls", func(t *testing.T) {
+		m1 := Message{ID: "1", CreatedAt: base}
+		m2 := Message{ID: "2", CreatedAt: base.Add(time.Hour)}
+		m3 := Message{ID: "3", CreatedAt: base.Add(2 * time.Hour)}
+
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(isRequest(http.MethodGet, path)).Return(newMessagesResponse(t, m2, m1), nil).Once()
+		mDoer.EXPECT().Do(isRequest(http.MethodGet, path)).Return(newMessagesResponse(t, m3, m2, m1), nil).Once()
+		mDoer.EXPECT().Do(isRequest(http.MethodGet, path)).Return(newMessagesResponse(t, m3, m2, m1), nil).Once()
+
+		c := newClient()
+		c.doer = mDoer
+		messages, cursor, _, err := c.ListNewMessages(context.Background(), "user@example.com", MessageCursor{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"1", "2"}, messageIDs(messages))
+		assert.Equal(t, MessageCursor{CreatedAt: m2.CreatedAt, IDs: []string{"2"}}, cursor)
+
+		messages, cursor, _, err = c.ListNewMessages(context.Background(), "user@example.com", cursor)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"3"}, messageIDs(messages))
+		assert.Equal(t, MessageCursor{CreatedAt: m3.CreatedAt, IDs: []string{"3"}}, cursor)
+
+		messages, next, _, err := c.ListNewMessages(context.Background(), "user@example.com", cursor)
+		require.NoError(t, err)
+		assert.Empty(t, messages)
+		assert.Equal(t, cursor, next)
+	})
+
+	t.Run("identical timestamps", func(t *testing.T) {
+		m1 := Message{ID: "1", CreatedAt: base}
+		m2 := Message{ID: "2", CreatedAt: base}
+		m3 := Message{ID: "3", CreatedAt: base}
+
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(isRequest(http.MethodGet, path)).Return(newMessagesResponse(t, m2), nil).Once()
+		mDoer.EXPECT().Do(isRequest(http.MethodGet, path)).Return(newMessagesResponse(t, m3, m2, m1), nil).Once()
+
+		c := newClient()
+		c.doer = mDoer
+		messages, cursor, _, err := c.ListNewMessages(context.Background(), "user@example.com", MessageCursor{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"2"}, messageIDs(messages))
+
+		messages, cursor, _, err = c.ListNewMessages(context.Background(), "user@example.com", cursor)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"1", "3"}, messageIDs(messages))
+		assert.Equal(t, MessageCursor{CreatedAt: base, IDs: []string{"1", "2", "3"}}, cursor)
+	})
+
+	t.Run("clock skew", func(t *testing.T) {
+		newest := Message{ID: "2", CreatedAt: base}
+		late := Message{ID: "1", CreatedAt: base.Add(-MessageCursorSkew / 2)}
+		old := Message{ID: "0", CreatedAt: base.Add(-2 * MessageCursorSkew)}
+
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(isRequest(http.MethodGet, path)).Return(newMessagesResponse(t, newest, late, old), nil).Once()
+
+		c := newClient()
+		c.doer = mDoer
+		messages, cursor, _, err := c.ListNewMessages(context.Background(), "user@example.com", MessageCursor{
+			CreatedAt: base,
+			IDs:       []string{"2"},
+		})
+		require.NoError(t, err)
+		// The late message is within the skew window, while the old one is before the cursor.
+		assert.Equal(t, []string{"1"}, messageIDs(messages))
+		assert.Equal(t, MessageCursor{CreatedAt: base, IDs: []string{"1", "2"}}, cursor)
+	})
+
+	t.Run("error from ListEmailMessages", func(t *testing.T) {
+		c := newClient()
+		cursor := MessageCursor{CreatedAt: base, IDs: []string{"1"}}
+		_, next, _, err := c.ListNewMessages(nil, "user@example.com", cursor)
+		assert.EqualError(t, err, "net/http: nil Context")
+		assert.Equal(t, cursor, next)
+	})
+}
+
+func TestMessageCursor_JSON(t *testing.T) {
+	cursor := MessageCursor{CreatedAt: time.Date(2025, 1, 31, 22, 0, 0, 0, time.UTC), IDs: []string{"1"}}
+	b, err := json.Marshal(cursor)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"created_at":"2025-01-31T22:00:00Z","ids":["1"]}`, string(b))
+
+	var decoded MessageCursor
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, cursor, decoded)
+}
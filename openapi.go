@@ -0,0 +1,15 @@
+package tempmail
+
+import (
+	_ "embed"
+)
+
+//go:embed openapi.json
+var openAPISpec []byte
+
+// OpenAPISpec returns the OpenAPI 3 document of the API endpoints wrapped by the Client,
+// in JSON. It can be used to generate documentation or to run a fake server in tests.
+// The Client's requests and the recorded responses in testdata are validated against it.
+func OpenAPISpec() []byte {
+	return append([]byte(nil), openAPISpec...)
+}
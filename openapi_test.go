@@ -0,0 +1,404 @@
+package tempmail
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/mail"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// openAPIDoc is the subset of an OpenAPI 3 document checked by the tests.
+type openAPIDoc struct {
+	Paths      map[string]map[string]*openAPIOperation `json:"paths"`
+	Components struct {
+		Schemas         map[string]*openAPISchema    `json:"schemas"`
+		Parameters      map[string]*openAPIParameter `json:"parameters"`
+		Responses       map[string]*openAPIResponse  `json:"responses"`
+		SecuritySchemes map[string]struct {
+			In   string `json:"in"`
+			Name string `json:"name"`
+		} `json:"securitySchemes"`
+	} `json:"components"`
+}
+
+type openAPIOperation struct {
+	OperationID string              `json:"operationId"`
+	Parameters  []*openAPIParameter `json:"parameters"`
+	RequestBody *struct {
+		Required bool                        `json:"required"`
+		Content  map[string]openAPIMediaType `json:"content"`
+	} `json:"requestBody"`
+	Responses map[string]*openAPIResponse `json:"responses"`
+}
+
+type openAPIParameter struct {
+	Ref      string         `json:"$ref"`
+	Name     string         `json:"name"`
+	In       string         `json:"in"`
+	Required bool           `json:"required"`
+	Schema   *openAPISchema `json:"schema"`
+}
+
+type openAPIResponse struct {
+	Ref     string                      `json:"$ref"`
+	Content map[string]openAPIMediaType `json:"content"`
+}
+
+type openAPIMediaType struct {
+	Schema *openAPISchema `json:"schema"`
+}
+
+type openAPISchema struct {
+	Ref                  string                    `json:"$ref"`
+	Type                 string                    `json:"type"`
+	Format               string                    `json:"format"`
+	Nullable             bool                      `json:"nullable"`
+	Enum                 []string                  `json:"enum"`
+	Required             []string                  `json:"required"`
+	Properties           map[string]*openAPISchema `json:"properties"`
+	AdditionalProperties *bool                     `json:"additionalProperties"`
+	Items                *openAPISchema            `json:"items"`
+}
+
+// loadOpenAPI parses the embedded OpenAPI document.
+func loadOpenAPI(t *testing.T) *openAPIDoc {
+	t.Helper()
+	var doc openAPIDoc
+	require.NoError(t, json.Unmarshal(OpenAPISpec(), &doc))
+	return &doc
+}
+
+// operation returns the operation of the method and path, and the path parameters.
+func (d *openAPIDoc) operation(method, path string) (*openAPIOperation, map[string]string, error) {
+	for template, item := range d.Paths {
+		params, ok := matchPath(template, path)
+		if !ok {
+			continue
+		}
+		op, ok := item[strings.ToLower(method)]
+		if !ok {
+			return nil, nil, fmt.Errorf("%s %s: method not allowed by %s", method, path, template)
+		}
+		return op, params, nil
+	}
+	return nil, nil, fmt.Errorf("%s %s: no such path", method, path)
+}
+
+// matchPath matches the path against the template and returns the path parameters.
+func matchPath(template, path string) (map[string]string, bool) {
+	want := strings.Split(strings.Trim(template, "/"), "/")
+	got := strings.Split(strings.Trim(path, "/"), "/")
+	if len(want) != len(got) {
+		return nil, false
+	}
+	params := make(map[string]string)
+	for i, segment := range want {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			if got[i] == "" {
+				return nil, false
+			}
+			params[strings.Trim(segment, "{}")] = got[i]
+			continue
+		}
+		if segment != got[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+// validateRequest checks the request against the document.
+func (d *openAPIDoc) validateRequest(req *http.Request) error {
+	op, params, err := d.operation(req.Method, req.URL.Path)
+	if err != nil {
+		return err
+	}
+	if got := operationFromContext(req.Context()); got != "" && string(got) != op.OperationID {
+		return fmt.Errorf("%s %s: operation %s, want %s", req.Method, req.URL.Path, got, op.OperationID)
+	}
+	for _, scheme := range d.Components.SecuritySchemes {
+		if scheme.In == "header" && req.Header.Get(scheme.Name) == "" {
+			return fmt.Errorf("%s %s: missing %s header", req.Method, req.URL.Path, scheme.Name)
+		}
+	}
+	for _, p := range op.Parameters {
+		p = d.parameter(p)
+		if p.In != "path" {
+			continue
+		}
+		if err := d.validateValue(p.Schema, params[p.Name], "path."+p.Name); err != nil {
+			return fmt.Errorf("%s %s: %w", req.Method, req.URL.Path, err)
+		}
+	}
+
+	var body []byte
+	if req.Body != nil {
+		body, err = io.ReadAll(req.Body)
+		if err != nil {
+			return err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	if op.RequestBody == nil {
+		if len(body) > 0 {
+			return fmt.Errorf("%s %s: unexpected request body", req.Method, req.URL.Path)
+		}
+		return nil
+	}
+	if len(body) == 0 {
+		if op.RequestBody.Required {
+			return fmt.Errorf("%s %s: missing request body", req.Method, req.URL.Path)
+		}
+		return nil
+	}
+	media, ok := op.RequestBody.Content["application/json"]
+	if !ok {
+		return fmt.Errorf("%s %s: JSON request body not allowed", req.Method, req.URL.Path)
+	}
+	return d.validateJSON(media.Schema, body, "body")
+}
+
+// validateResponse checks the JSON response body of the method and path with the status code.
+func (d *openAPIDoc) validateResponse(method, path string, statusCode int, body []byte) error {
+	op, _, err := d.operation(method, path)
+	if err != nil {
+		return err
+	}
+	resp, ok := op.Responses[fmt.Sprint(statusCode)]
+	if !ok {
+		resp, ok = op.Responses["default"]
+	}
+	if !ok {
+		return fmt.Errorf("%s %s: undocumented status %d", method, path, statusCode)
+	}
+	if resp.Ref != "" {
+		resp = d.Components.Responses[strings.TrimPrefix(resp.Ref, "#/components/responses/")]
+	}
+	media, ok := resp.Content["application/json"]
+	if !ok {
+		return fmt.Errorf("%s %s: status %d has no JSON body", method, path, statusCode)
+	}
+	return d.validateJSON(media.Schema, body, "body")
+}
+
+// parameter resolves a parameter reference.
+func (d *openAPIDoc) parameter(p *openAPIParameter) *openAPIParameter {
+	if p.Ref != "" {
+		return d.Components.Parameters[strings.TrimPrefix(p.Ref, "#/components/parameters/")]
+	}
+	return p
+}
+
+// schema resolves a schema reference.
+func (d *openAPIDoc) schema(s *openAPISchema) *openAPISchema {
+	for s != nil && s.Ref != "" {
+		s = d.Components.Schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")]
+	}
+	return s
+}
+
+func (d *openAPIDoc) validateJSON(s *openAPISchema, data []byte, path string) error {
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return d.validateValue(s, v, path)
+}
+
+// validateValue checks a decoded JSON value against the schema.
+func (d *openAPIDoc) validateValue(s *openAPISchema, v interface{}, path string) error {
+	s = d.schema(s)
+	if s == nil {
+		return fmt.Errorf("%s: unresolved schema", path)
+	}
+	if v == nil {
+		if s.Nullable {
+			return nil
+		}
+		return fmt.Errorf("%s: null is not allowed", path)
+	}
+
+	switch s.Type {
+	case "object":
+		object, ok := v.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: want object, got %T", path, v)
+		}
+		for _, name := range s.Required {
+			if _, ok := object[name]; !ok {
+				return fmt.Errorf("%s: missing required property %q", path, name)
+			}
+		}
+		names := make([]string, 0, len(object))
+		for name := range object {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			prop, ok := s.Properties[name]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					return fmt.Errorf("%s: unknown property %q", path, name)
+				}
+				continue
+			}
+			if err := d.validateValue(prop, object[name], path+"."+name); err != nil {
+				return err
+			}
+		}
+	case "array":
+		items, ok := v.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s: want array, got %T", path, v)
+		}
+		for i, item := range items {
+			if err := d.validateValue(s.Items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case "integer":
+		n, ok := v.(json.Number)
+		if !ok {
+			return fmt.Errorf("%s: want integer, got %T", path, v)
+		}
+		if _, err := n.Int64(); err != nil {
+			return fmt.Errorf("%s: want integer, got %s", path, n)
+		}
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("%s: want string, got %T", path, v)
+		}
+		if len(s.Enum) > 0 && !containsString(s.Enum, str) {
+			return fmt.Errorf("%s: %q is not one of %v", path, str, s.Enum)
+		}
+		switch s.Format {
+		case "email":
+			if _, err := mail.ParseAddress(str); err != nil {
+				return fmt.Errorf("%s: invalid email %q", path, str)
+			}
+		case "date-time":
+			if _, err := time.Parse(time.RFC3339, str); err != nil {
+				return fmt.Errorf("%s: invalid date-time %q", path, str)
+			}
+		}
+	default:
+		return fmt.Errorf("%s: unsupported schema type %q", path, s.Type)
+	}
+	return nil
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// TestOpenAPI_requests checks every request built by the Client against the OpenAPI document,
+// and that every documented operation is used by the Client.
+func TestOpenAPI_requests(t *testing.T) {
+	doc := loadOpenAPI(t)
+	used := make(map[string]bool)
+
+	mDoer := newMockDoer(t)
+	mDoer.EXPECT().Do(mock.Anything).RunAndReturn(func(req *http.Request) (*http.Response, error) {
+		assert.NoError(t, doc.validateRequest(req))
+		if op, _, err := doc.operation(req.Method, req.URL.Path); err == nil {
+			used[op.OperationID] = true
+		}
+		return newTestResponse(http.StatusOK, []byte(`{}`)), nil
+	})
+
+	c := NewClient("API_KEY", nil, WithDefaultTimeouts(Timeouts{}))
+	c.doer = mDoer
+	ctx := context.Background()
+	const email = "user@example.com"
+
+	_, _, _ = c.CreateEmail(ctx, CreateEmailOptions{})
+	_, _, _ = c.CreateEmail(ctx, CreateEmailOptions{Email: email})
+	_, _, _ = c.CreateEmail(ctx, CreateEmailOptions{DomainType: DomainTypePremium, Domain: "example.com"})
+	_, _, _ = c.With(WithIdempotencyKey("create-1")).CreateEmail(ctx, CreateEmailOptions{})
+	_, _, _ = c.ListEmailMessages(ctx, email)
+	_, _ = c.DeleteEmail(ctx, email)
+	_, _, _ = c.GetMessage(ctx, testMessageID)
+	_, _, _ = c.GetMessageSourceCode(ctx, testMessageID)
+	_, _ = c.DeleteMessage(ctx, testMessageID)
+	_, _, _ = c.DownloadAttachment(ctx, "01JE97K1PBYVGKY0PVE3KXSBF9")
+	_, _, _ = c.ListDomains(ctx)
+	_, _, _ = c.RateLimit(ctx)
+
+	for _, item := range doc.Paths {
+		for _, op := range item {
+			assert.True(t, used[op.OperationID], "operation %s is not used by the client", op.OperationID)
+		}
+	}
+}
+
+// TestOpenAPI_fixtures checks the recorded responses in testdata against the OpenAPI document.
+func TestOpenAPI_fixtures(t *testing.T) {
+	doc := loadOpenAPI(t)
+	fixtures := map[string]struct {
+		method     string
+		path       string
+		statusCode int
+	}{
+		"create_email.json":        {http.MethodPost, "/v1/emails", http.StatusOK},
+		"error_response.json":      {http.MethodGet, "/v1/attachments/1", http.StatusNotFound},
+		"get_message.json":         {http.MethodGet, "/v1/messages/1", http.StatusOK},
+		"list_domains.json":        {http.MethodGet, "/v1/domains", http.StatusOK},
+		"list_email_messages.json": {http.MethodGet, "/v1/emails/user@example.com/messages", http.StatusOK},
+		"rate_limit.json":          {http.MethodGet, "/v1/rate_limit", http.StatusOK},
+	}
+
+	files, err := filepath.Glob("testdata/*.json")
+	require.NoError(t, err)
+	for _, file := range files {
+		name := filepath.Base(file)
+		t.Run(name, func(t *testing.T) {
+			f, ok := fixtures[name]
+			require.True(t, ok, "no endpoint for fixture %s", name)
+			assert.NoError(t, doc.validateResponse(f.method, f.path, f.statusCode, readFile(t, file)))
+		})
+	}
+}
+
+func TestOpenAPI_validator(t *testing.T) {
+	doc := loadOpenAPI(t)
+
+	newReq := func(method, path, body string) *http.Request {
+		req, err := http.NewRequest(method, baseURL+path, strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set(headerAPIKey, "API_KEY")
+		return req
+	}
+	assert.NoError(t, doc.validateRequest(newReq(http.MethodPost, "/v1/emails", `{"domain_type":"custom"}`)))
+	assert.ErrorContains(t, doc.validateRequest(newReq(http.MethodPost, "/v1/emails", `{"domain_type":"other"}`)), "is not one of")
+	assert.ErrorContains(t, doc.validateRequest(newReq(http.MethodPost, "/v1/emails", `{"name":"x"}`)), `unknown property "name"`)
+	assert.ErrorContains(t, doc.validateRequest(newReq(http.MethodGet, "/v1/emails", "")), "method not allowed")
+	assert.ErrorContains(t, doc.validateRequest(newReq(http.MethodGet, "/v1/unknown", "")), "no such path")
+	assert.ErrorContains(t, doc.validateRequest(newReq(http.MethodGet, "/v1/domains", "{}")), "unexpected request body")
+
+	req := newReq(http.MethodGet, "/v1/domains", "")
+	req.Header.Del(headerAPIKey)
+	assert.ErrorContains(t, doc.validateRequest(req), "missing X-API-Key header")
+
+	assert.ErrorContains(t, doc.validateResponse(http.MethodGet, "/v1/rate_limit", http.StatusOK, []byte(`{"limit":"1","used":0,"remaining":1,"reset":0}`)), "want integer")
+	assert.ErrorContains(t, doc.validateResponse(http.MethodGet, "/v1/domains", http.StatusOK, []byte(`{"domains":[{"name":"a"}]}`)), `missing required property "type"`)
+}
@@ -0,0 +1,68 @@
+package tempmail
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode"
+)
+
+// ValidationError is returned before any request is sent when an argument can't be used in an API call.
+type ValidationError struct {
+	// Field is the name of the invalid argument, for example "email" or "messageID".
+	Field string
+	// Value is the invalid value.
+	Value string
+	// Reason describes why the value is invalid.
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("tempmail: invalid %s %q: %s", e.Field, e.Value, e.Reason)
+}
+
+// buildPath builds an API path from the given segments, escaping every one of them.
+// Dynamic segments must be validated with validateSegment first.
+func buildPath(segments ...string) string {
+	var b strings.Builder
+	for _, s := range segments {
+		b.WriteByte('/')
+		b.WriteString(url.PathEscape(s))
+	}
+	return b.String()
+}
+
+// validateSegment checks that the value can be used as a single path segment.
+// Escaping takes care of reserved characters, but dot segments would still be resolved
+// by the server, and whitespace is never part of a valid address or ID.
+func validateSegment(field, value string) error {
+	switch value {
+	case "":
+		return &ValidationError{Field: field, Value: value, Reason: "must not be empty"}
+	case ".", "..":
+		return &ValidationError{Field: field, Value: value, Reason: "must not be a dot segment"}
+	}
+	for _, r := range value {
+		if unicode.IsSpace(r) || unicode.IsControl(r) {
+			return &ValidationError{Field: field, Value: value, Reason: "must not contain whitespace or control characters"}
+		}
+	}
+	return nil
+}
+
+// validateID checks an ID of a message or an attachment.
+func validateID(field, id string) error {
+	return validateSegment(field, id)
+}
+
+// validateEmail checks an email address used in a path.
+func validateEmail(email string) error {
+	if err := validateSegment("email", email); err != nil {
+		return err
+	}
+	at := strings.LastIndexByte(email, '@')
+	if at <= 0 || at == len(email)-1 {
+		return &ValidationError{Field: "email", Value: email, Reason: "must have a local part and a domain"}
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package tempmail
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildPath(t *testing.T) {
+	tests := []struct {
+		name     string
+		segments []string
+		expected string
+	}{
+		{name: "plain", segments: []string{"v1", "emails", "test@example.com", "messages"}, expected: "/v1/emails/test@example.com/messages"},
+		{name: "slash", segments: []string{"v1", "messages", "a/b"}, expected: "/v1/messages/a%2Fb"},
+		{name: "query", segments: []string{"v1", "messages", "a?b=c"}, expected: "/v1/messages/a%3Fb=c"},
+		{name: "fragment", segments: []string{"v1", "messages", "a#b"}, expected: "/v1/messages/a%23b"},
+		{name: "percent", segments: []string{"v1", "messages", "a%2Fb"}, expected: "/v1/messages/a%252Fb"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, buildPath(tt.segments...))
+		})
+	}
+}
+
+func TestValidateEmail(t *testing.T) {
+	assert.NoError(t, validateEmail("test@example.com"))
+	assert.NoError(t, validateEmail("a/b?c#d%e@example.com"))
+
+	for _, email := range []string{"", ".", "..", "test", "@example.com", "test@", "te st@example.com", "test@example.com\n"} {
+		err := validateEmail(email)
+		var validationErr *ValidationError
+		require.ErrorAs(t, err, &validationErr, "email %q", email)
+		assert.Equal(t, "email", validationErr.Field)
+		assert.Equal(t, email, validationErr.Value)
+	}
+}
+
+func TestValidateID(t *testing.T) {
+	assert.NoError(t, validateID("messageID", "01JE97FT950QRPDYGDXJ4R43QR"))
+	assert.EqualError(t, validateID("messageID", ""), `tempmail: invalid messageID "": must not be empty`)
+	assert.EqualError(t, validateID("messageID", ".."), `tempmail: invalid messageID "..": must not be a dot segment`)
+	assert.EqualError(t, validateID("attachmentID", "a\tb"), `tempmail: invalid attachmentID "a\tb": must not contain whitespace or control characters`)
+}
+
+// fuzzPath checks that any value passed to call either fails validation without a request,
+// or ends up as exactly one path segment at the given index.
+func fuzzPath(f *testing.F, seeds []string, index int, call func(c *Client, value string) error) {
+	for _, s := range seeds {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, value string) {
+		var sent *http.Request
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(mock.Anything).RunAndReturn(func(req *http.Request) (*http.Response, error) {
+			sent = req
+			return newTestResponse(http.StatusOK, []byte("{}")), nil
+		}).Maybe()
+
+		c := newClient()
+		c.doer = mDoer
+		err := call(c, value)
+
+		var validationErr *ValidationError
+		if errors.As(err, &validationErr) {
+			assert.Nil(t, sent, "request sent for invalid value %q", value)
+			return
+		}
+		require.NotNil(t, sent, "request not sent for value %q: %v", value, err)
+		assert.Empty(t, sent.URL.RawQuery)
+		assert.Empty(t, sent.URL.Fragment)
+		assert.Equal(t, "api.temp-mail.io", sent.URL.Host)
+
+		segments := strings.Split(sent.URL.EscapedPath(), "/")
+		require.Greater(t, len(segments), index, "path %q", sent.URL.EscapedPath())
+		segment, err := url.PathUnescape(segments[index])
+		require.NoError(t, err)
+		assert.Equal(t, value, segment)
+	})
+}
+
+var (
+	fuzzEmailSeeds = []string{"test@example.com", "a/b@example.com", "a?b@example.com", "a#b@example.com", "a%2F@example.com", "../@example.com"}
+	fuzzIDSeeds    = []string{"01JE97FT950QRPDYGDXJ4R43QR", "a/b", "a?b", "a#b", "%2F", "..", "../rate_limit"}
+)
+
+func FuzzClient_DeleteEmail(f *testing.F) {
+	fuzzPath(f, fuzzEmailSeeds, 3, func(c *Client, value string) error {
+		_, err := c.DeleteEmail(context.Background(), value)
+		return err
+	})
+}
+
+func FuzzClient_ListEmailMessages(f *testing.F) {
+	fuzzPath(f, fuzzEmailSeeds, 3, func(c *Client, value string) error {
+		_, _, err := c.ListEmailMessages(context.Background(), value)
+		return err
+	})
+}
+
+func FuzzClient_DeleteMessage(f *testing.F) {
+	fuzzPath(f, fuzzIDSeeds, 3, func(c *Client, value string) error {
+		_, err := c.DeleteMessage(context.Background(), value)
+		return err
+	})
+}
+
+func FuzzClient_GetMessage(f *testing.F) {
+	fuzzPath(f, fuzzIDSeeds, 3, func(c *Client, value string) error {
+		_, _, err := c.GetMessage(context.Background(), value)
+		return err
+	})
+}
+
+func FuzzClient_GetMessageSourceCode(f *testing.F) {
+	fuzzPath(f, fuzzIDSeeds, 3, func(c *Client, value string) error {
+		_, _, err := c.GetMessageSourceCode(context.Background(), value)
+		return err
+	})
+}
+
+func FuzzClient_DownloadAttachment(f *testing.F) {
+	fuzzPath(f, fuzzIDSeeds, 3, func(c *Client, value string) error {
+		_, _, err := c.DownloadAttachment(context.Background(), value)
+		return err
+	})
+}
@@ -0,0 +1,334 @@
+package tempmail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	defaultPoolSize          = 10
+	defaultPoolMinTTL        = time.Minute
+	defaultPoolRetryInterval = time.Second
+)
+
+// ErrPoolClosed is returned when acquiring a lease from a closed Pool.
+var ErrPoolClosed = errors.New("tempmail: pool is closed")
+
+// ErrPoolTTL is returned by Acquire when the API creates addresses whose TTL is
+// shorter than PoolOptions.MinTTL, so the pool can never lease one.
+var ErrPoolTTL = errors.New("tempmail: address TTL is shorter than the pool MinTTL")
+
+// PoolOptions represents the options to create a Pool.
+type PoolOptions struct {
+	// Size is the number of addresses kept ready to be leased. Defaults to 10.
+	Size int
+	// CreateOptions are the options used to create every address of the pool.
+	CreateOptions CreateEmailOptions
+	// MinTTL is the minimum remaining lifetime of a leased address.
+	// Addresses expiring sooner are dropped from the pool and deleted. Defaults to 1 minute.
+	// If a newly created address already expires sooner, the pool stops and Acquire
+	// returns ErrPoolTTL.
+	MinTTL time.Duration
+	// Recycle makes Release clear the messages of the address and return it to the pool
+	// instead of deleting it.
+	Recycle bool
+	// RetryInterval is the wait time after a failed attempt to create an address,
+	// for example because of rate limiting. Defaults to 1 second.
+	RetryInterval time.Duration
+}
+
+// PoolStats represents the counters of a Pool.
+type PoolStats struct {
+	// Idle is the number of addresses ready to be leased.
+	Idle int
+	// Leased is the number of addresses currently leased.
+	Leased int
+	// Created is the number of addresses created by the pool.
+	Created int
+	// Recycled is the number of addresses returned to the pool after release.
+	Recycled int
+	// Deleted is the number of addresses deleted by the pool.
+	Deleted int
+	// Expired is the number of addresses dropped because they were about to expire.
+	Expired int
+	// CreateErrors is the number of failed attempts to create an address.
+	CreateErrors int
+}
+
+// Pool pre-creates email addresses in the background and hands out exclusive leases on them.
+// It is safe for concurrent use.
+type Pool struct {
+	client *Client
+	opts   PoolOptions
+
+	// idle holds the addresses ready to be leased.
+	idle chan poolEntry
+	// slots holds a token for every address the pool still has to create.
+	slots chan struct{}
+	stop  chan struct{}
+	done  chan struct{}
+
+	mu     sync.Mutex
+	stats  PoolStats
+	closed bool
+	// err is the error that stopped filling the pool, if any.
+	err error
+}
+
+// poolEntry is an address owned by the pool.
+type poolEntry struct {
+	email     string
+	expiresAt time.Time
+}
+
+// Lease is an exclusive lease on an address of a Pool.
+type Lease struct {
+	// Email is the leased email address.
+	Email string
+	// ExpiresAt is the time at which the address expires.
+	ExpiresAt time.Time
+
+	pool    *Pool
+	release sync.Once
+}
+
+// NewPool creates a Pool and starts filling it in the background.
+// Close the Pool to stop it and delete the addresses that were never leased.
+func NewPool(c *Client, opts PoolOptions) *Pool {
+	if opts.Size <= 0 {
+		opts.Size = defaultPoolSize
+	}
+	if opts.MinTTL <= 0 {
+		opts.MinTTL = defaultPoolMinTTL
+	}
+	if opts.RetryInterval <= 0 {
+		opts.RetryInterval = defaultPoolRetryInterval
+	}
+	p := &Pool{
+		client: c,
+		opts:   opts,
+		idle:   make(chan poolEntry, opts.Size),
+		slots:  make(chan struct{}, opts.Size),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	for i := 0; i < opts.Size; i++ {
+		p.slots <- struct{}{}
+	}
+	go p.fill()
+	return p
+}
+
+// fill creates addresses for free pool slots until the pool is closed.
+func (p *Pool) fill() {
+	defer close(p.done)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		<-p.stop
+		cancel()
+	}()
+
+	for {
+		select {
+		case <-p.slots:
+		case <-p.stop:
+			return
+		}
+
+		resp, _, err := p.client.CreateEmail(ctx, p.opts.CreateOptions)
+		if err == nil {
+			p.count(func(s *PoolStats) { s.Created++ })
+			entry := poolEntry{email: resp.Email, expiresAt: resp.ExpiresAt}
+			if p.expiring(entry) {
+				// Every new address would be dropped too, so creating more only wastes the rate limit.
+				p.drop(context.Background(), entry)
+				p.mu.Lock()
+				p.err = fmt.Errorf("%w: got %s, want at least %s", ErrPoolTTL, resp.TTL, p.opts.MinTTL)
+				p.mu.Unlock()
+				return
+			}
+			// The pool can only be full here if a released address was recycled meanwhile.
+			select {
+			case p.idle <- entry:
+			case <-p.stop:
+				_ = p.delete(context.Background(), entry.email)
+				return
+			}
+			continue
+		}
+		if ctx.Err() != nil {
+			return
+		}
+
+		// Give the slot back, so a released address can be recycled while waiting.
+		p.count(func(s *PoolStats) { s.CreateErrors++ })
+		p.freeSlot()
+		select {
+		case <-p.stop:
+			return
+		case <-time.After(p.opts.RetryInterval):
+		}
+	}
+}
+
+// Acquire leases an address, waiting for one to become available if the pool is empty.
+func (p *Pool) Acquire(ctx context.Context) (*Lease, error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-p.done:
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			if p.err != nil {
+				return nil, p.err
+			}
+			return nil, ErrPoolClosed
+		case entry := <-p.idle:
+			p.freeSlot()
+			if p.expiring(entry) {
+				p.drop(ctx, entry)
+				continue
+			}
+			p.count(func(s *PoolStats) { s.Leased++ })
+			return &Lease{
+				Email:     entry.email,
+				ExpiresAt: entry.expiresAt,
+				pool:      p,
+			}, nil
+		}
+	}
+}
+
+// Release ends the lease. Depending on PoolOptions.Recycle, the address is either cleared
+// and returned to the pool or deleted. Releasing a lease twice, even concurrently, is a no-op.
+func (l *Lease) Release(ctx context.Context) error {
+	var err error
+	l.release.Do(func() {
+		err = l.doRelease(ctx)
+	})
+	return err
+}
+
+// doRelease ends the lease.
+func (l *Lease) doRelease(ctx context.Context) error {
+	p := l.pool
+	p.count(func(s *PoolStats) { s.Leased-- })
+
+	entry := poolEntry{email: l.Email, expiresAt: l.ExpiresAt}
+	if !p.opts.Recycle || p.expiring(entry) {
+		return p.delete(ctx, entry.email)
+	}
+	if _, err := p.client.PurgeInbox(ctx, entry.email); err != nil {
+		return errors.Join(err, p.delete(ctx, entry.email))
+	}
+
+	if p.recycle(entry) {
+		return nil
+	}
+	// The pool is full or closed, so the address is no longer needed.
+	return p.delete(ctx, entry.email)
+}
+
+// recycle returns the address to the pool unless the pool is full or closed.
+func (p *Pool) recycle(entry poolEntry) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return false
+	}
+	select {
+	case p.idle <- entry:
+	default:
+		return false
+	}
+	p.stats.Recycled++
+	// Take the slot of the recycled address, so no new address is created for it.
+	select {
+	case <-p.slots:
+	default:
+	}
+	return true
+}
+
+// freeSlot makes room for one more address in the pool.
+func (p *Pool) freeSlot() {
+	select {
+	case p.slots <- struct{}{}:
+	default:
+	}
+}
+
+// delete deletes the address and counts it once it is deleted.
+// An already expired address is not an error.
+func (p *Pool) delete(ctx context.Context, email string) error {
+	_, err := p.client.DeleteEmail(ctx, email)
+	if errors.Is(err, ErrEmailExpired) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	p.count(func(s *PoolStats) { s.Deleted++ })
+	return nil
+}
+
+// drop deletes an address that is about to expire. A failure is not reported,
+// since the address expires soon anyway.
+func (p *Pool) drop(ctx context.Context, entry poolEntry) {
+	p.count(func(s *PoolStats) { s.Expired++ })
+	_ = p.delete(ctx, entry.email)
+}
+
+// expiring reports whether the address expires within PoolOptions.MinTTL.
+func (p *Pool) expiring(entry poolEntry) bool {
+	return entry.expiresAt.Sub(p.client.now()) < p.opts.MinTTL
+}
+
+// count updates the pool stats.
+func (p *Pool) count(update func(s *PoolStats)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	update(&p.stats)
+}
+
+// Stats returns the current pool counters.
+func (p *Pool) Stats() PoolStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	stats := p.stats
+	stats.Idle = len(p.idle)
+	return stats
+}
+
+// Close stops filling the pool and deletes all idle addresses.
+// Leased addresses are deleted when they are released.
+func (p *Pool) Close(ctx context.Context) error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	p.mu.Unlock()
+
+	close(p.stop)
+	<-p.done
+
+	var errs []error
+	for {
+		select {
+		case entry := <-p.idle:
+			if err := p.delete(ctx, entry.email); err != nil {
+				errs = append(errs, err)
+			}
+		default:
+			return errors.Join(errs...)
+		}
+	}
+}
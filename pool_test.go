@@ -0,0 +1,203 @@
+package tempmail
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newPoolTestClient returns a client that creates addresses with the given TTL
+// and accepts any other request.
+func newPoolTestClient(t *testing.T, ttl int) (*Client, *int32) {
+	var created int32
+	mDoer := newMockDoer(t)
+	mDoer.EXPECT().Do(isRequest(http.MethodPost, "/v1/emails")).
+		RunAndReturn(func(*http.Request) (*http.Response, error) {
+			n := atomic.AddInt32(&created, 1)
+			body := fmt.Sprintf(`{"email":"user%d@example.com","ttl":%d}`, n, ttl)
+			return newTestResponse(http.StatusOK, []byte(body)), nil
+		}).Maybe()
+	mDoer.EXPECT().Do(mock.Anything).
+		RunAndReturn(func(req *http.Request) (*http.Response, error) {
+			if req.Method == http.MethodGet && strings.HasSuffix(req.URL.Path, "/messages") {
+				return newTestResponse(http.StatusOK, readFile(t, "testdata/list_email_messages.json")), nil
+			}
+			return newTestResponse(http.StatusOK, []byte("{}")), nil
+		}).Maybe()
+
+	c := newClient()
+	c.doer = mDoer
+	return c, &created
+}
+
+func TestPool(t *testing.T) {
+	t.Run("acquire and delete on release", func(t *testing.T) {
+		c, _ := newPoolTestClient(t, 3600)
+		p := NewPool(c, PoolOptions{Size: 2})
+
+		lease, err := p.Acquire(context.Background())
+		require.NoError(t, err)
+		assert.True(t, strings.HasSuffix(lease.Email, "@example.com"))
+		assert.WithinDuration(t, time.Now().Add(time.Hour), lease.ExpiresAt, time.Minute)
+		assert.Equal(t, 1, p.Stats().Leased)
+
+		require.NoError(t, lease.Release(context.Background()))
+		require.NoError(t, lease.Release(context.Background()))
+
+		require.NoError(t, p.Close(context.Background()))
+		stats := p.Stats()
+		assert.Zero(t, stats.Leased)
+		assert.Zero(t, stats.Idle)
+		assert.Equal(t, stats.Created, stats.Deleted)
+	})
+
+	t.Run("exclusive leases", func(t *testing.T) {
+		c, _ := newPoolTestClient(t, 3600)
+		p := NewPool(c, PoolOptions{Size: 3})
+		defer p.Close(context.Background())
+
+		seen := make(map[string]bool)
+		for i := 0; i < 10; i++ {
+			lease, err := p.Acquire(context.Background())
+			require.NoError(t, err)
+			assert.False(t, seen[lease.Email], "address leased twice: %s", lease.Email)
+			seen[lease.Email] = true
+		}
+		assert.Equal(t, 10, p.Stats().Leased)
+	})
+
+	t.Run("recycle", func(t *testing.T) {
+		// Only the first address can be created, so the pool has to recycle it.
+		var created, listed int32
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(mock.Anything).
+			RunAndReturn(func(req *http.Request) (*http.Response, error) {
+				switch {
+				case req.Method == http.MethodPost:
+					if atomic.AddInt32(&created, 1) > 1 {
+						return nil, assert.AnError
+					}
+					return newTestResponse(http.StatusOK, readFile(t, "testdata/create_email.json")), nil
+				case req.Method == http.MethodGet:
+					// The inbox is empty once its messages have been deleted.
+					if atomic.AddInt32(&listed, 1) > 1 {
+						return newTestResponse(http.StatusOK, []byte(`{"messages":[]}`)), nil
+					}
+					return newTestResponse(http.StatusOK, readFile(t, "testdata/list_email_messages.json")), nil
+				default:
+					return newTestResponse(http.StatusOK, []byte("{}")), nil
+				}
+			})
+		c := newClient()
+		c.doer = mDoer
+
+		p := NewPool(c, PoolOptions{Size: 1, Recycle: true, RetryInterval: time.Hour})
+		defer p.Close(context.Background())
+
+		lease, err := p.Acquire(context.Background())
+		require.NoError(t, err)
+		require.NoError(t, lease.Release(context.Background()))
+		assert.Equal(t, 1, p.Stats().Recycled)
+
+		lease, err = p.Acquire(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "test@example.com", lease.Email)
+		mDoer.AssertCalled(t, "Do", isRequest(http.MethodDelete, "/v1/messages/01JE97FT950QRPDYGDXJ4R43QR"))
+	})
+
+	t.Run("TTL shorter than MinTTL", func(t *testing.T) {
+		c, created := newPoolTestClient(t, 30)
+		p := NewPool(c, PoolOptions{Size: 3})
+		defer p.Close(context.Background())
+
+		_, err := p.Acquire(context.Background())
+		assert.ErrorIs(t, err, ErrPoolTTL)
+		assert.ErrorContains(t, err, "got 30s, want at least 1m0s")
+		// The pool stops after the first address instead of creating replacements.
+		assert.Equal(t, int32(1), atomic.LoadInt32(created))
+		stats := p.Stats()
+		assert.Equal(t, 1, stats.Expired)
+		assert.Equal(t, 1, stats.Deleted)
+	})
+
+	t.Run("concurrent release", func(t *testing.T) {
+		c, _ := newPoolTestClient(t, 3600)
+		p := NewPool(c, PoolOptions{Size: 1})
+		defer p.Close(context.Background())
+
+		lease, err := p.Acquire(context.Background())
+		require.NoError(t, err)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.NoError(t, lease.Release(context.Background()))
+			}()
+		}
+		wg.Wait()
+		stats := p.Stats()
+		assert.Zero(t, stats.Leased)
+		assert.Equal(t, 1, stats.Deleted)
+	})
+
+	t.Run("failed deletes are not counted", func(t *testing.T) {
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(isRequest(http.MethodPost, "/v1/emails")).
+			Return(newTestResponse(http.StatusOK, readFile(t, "testdata/create_email.json")), nil).Once()
+		mDoer.EXPECT().Do(isRequest(http.MethodPost, "/v1/emails")).Return(nil, assert.AnError).Maybe()
+		mDoer.EXPECT().Do(isRequest(http.MethodDelete, "/v1/emails/test@example.com")).Return(nil, assert.AnError).Once()
+		c := newClient()
+		c.doer = mDoer
+
+		p := NewPool(c, PoolOptions{Size: 1, RetryInterval: time.Hour})
+		defer p.Close(context.Background())
+
+		lease, err := p.Acquire(context.Background())
+		require.NoError(t, err)
+		assert.ErrorIs(t, lease.Release(context.Background()), assert.AnError)
+		assert.Zero(t, p.Stats().Deleted)
+	})
+
+	t.Run("create errors are retried", func(t *testing.T) {
+		var calls int32
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(mock.Anything).
+			RunAndReturn(func(req *http.Request) (*http.Response, error) {
+				if req.Method == http.MethodPost && atomic.AddInt32(&calls, 1) == 1 {
+					return nil, assert.AnError
+				}
+				return newTestResponse(http.StatusOK, readFile(t, "testdata/create_email.json")), nil
+			})
+		c := newClient()
+		c.doer = mDoer
+
+		p := NewPool(c, PoolOptions{Size: 1, RetryInterval: time.Millisecond})
+		defer p.Close(context.Background())
+
+		lease, err := p.Acquire(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, "test@example.com", lease.Email)
+		assert.Equal(t, 1, p.Stats().CreateErrors)
+	})
+
+	t.Run("acquire after close", func(t *testing.T) {
+		c, _ := newPoolTestClient(t, 3600)
+		p := NewPool(c, PoolOptions{Size: 1})
+		require.NoError(t, p.Close(context.Background()))
+		require.NoError(t, p.Close(context.Background()))
+
+		_, err := p.Acquire(context.Background())
+		assert.ErrorIs(t, err, ErrPoolClosed)
+	})
+}
@@ -0,0 +1,51 @@
+package tempmail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// maxPurgeRounds is the number of times PurgeInbox lists and deletes the messages.
+const maxPurgeRounds = 5
+
+// ErrInboxNotEmpty is returned by PurgeInbox when messages keep arriving during the purge.
+var ErrInboxNotEmpty = errors.New("tempmail: inbox not empty after purge")
+
+// PurgeInbox deletes all messages of the email address but keeps the address.
+// The messages are deleted concurrently, and listed again until the inbox is empty,
+// so messages arriving during the purge are deleted too. Messages deleted by someone
+// else in the meantime are not errors. It returns the number of deleted messages.
+func (c *Client) PurgeInbox(ctx context.Context, email string) (int, error) {
+	deleted := 0
+	for round := 0; round < maxPurgeRounds; round++ {
+		resp, _, err := c.ListEmailMessages(ctx, email)
+		if err != nil {
+			return deleted, err
+		}
+		if len(resp.Messages) == 0 {
+			return deleted, nil
+		}
+
+		ids := make([]string, len(resp.Messages))
+		for i, m := range resp.Messages {
+			ids[i] = m.ID
+		}
+		results, _ := c.DeleteMessages(ctx, ids, BulkOptions{StopOnFatal: true})
+
+		var errs []error
+		for _, r := range results {
+			switch {
+			case r.Err == nil:
+				deleted++
+			case isNotFound(r.Err), errors.Is(r.Err, ErrSkipped):
+			default:
+				errs = append(errs, fmt.Errorf("%s: %w", r.ID, r.Err))
+			}
+		}
+		if len(errs) > 0 {
+			return deleted, errors.Join(errs...)
+		}
+	}
+	return deleted, fmt.Errorf("%w: %s", ErrInboxNotEmpty, email)
+}
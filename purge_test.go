@@ -0,0 +1,100 @@
+package tempmail
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testMessagesPath = "/v1/emails/user@example.com/messages"
+
+// messagesBody returns a ListEmailMessages response body with the given message IDs.
+func messagesBody(ids ...string) []byte {
+	body := `{"messages":[`
+	for i, id := range ids {
+		if i > 0 {
+			body += ","
+		}
+		body += `{"id":"` + id + `"}`
+	}
+	return []byte(body + "]}")
+}
+
+func TestClient_PurgeInbox(t *testing.T) {
+	t.Run("messages arriving during the purge", func(t *testing.T) {
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(isRequest(http.MethodGet, testMessagesPath)).Return(newTestResponse(http.StatusOK, messagesBody("1", "2")), nil).Once()
+		mDoer.EXPECT().Do(isRequest(http.MethodGet, testMessagesPath)).Return(newTestResponse(http.StatusOK, messagesBody("3")), nil).Once()
+		mDoer.EXPECT().Do(isRequest(http.MethodGet, testMessagesPath)).Return(newTestResponse(http.StatusOK, messagesBody()), nil).Once()
+		for _, id := range []string{"1", "2", "3"} {
+			mDoer.EXPECT().Do(isRequest(http.MethodDelete, "/v1/messages/"+id)).Return(newTestResponse(http.StatusOK, nil), nil).Once()
+		}
+
+		c := newClient()
+		c.doer = mDoer
+		deleted, err := c.PurgeInbox(context.Background(), "user@example.com")
+		require.NoError(t, err)
+		assert.Equal(t, 3, deleted)
+	})
+
+	t.Run("already deleted", func(t *testing.T) {
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(isRequest(http.MethodGet, testMessagesPath)).Return(newTestResponse(http.StatusOK, messagesBody("1", "2")), nil).Once()
+		mDoer.EXPECT().Do(isRequest(http.MethodGet, testMessagesPath)).Return(newTestResponse(http.StatusOK, messagesBody()), nil).Once()
+		mDoer.EXPECT().Do(isRequest(http.MethodDelete, "/v1/messages/1")).Return(newTestResponse(http.StatusOK, nil), nil)
+		mDoer.EXPECT().Do(isRequest(http.MethodDelete, "/v1/messages/2")).
+			Return(newTestResponse(http.StatusNotFound, readFile(t, "testdata/error_response.json")), nil)
+
+		c := newClient()
+		c.doer = mDoer
+		deleted, err := c.PurgeInbox(context.Background(), "user@example.com")
+		require.NoError(t, err)
+		assert.Equal(t, 1, deleted)
+	})
+
+	t.Run("error from DeleteMessage", func(t *testing.T) {
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(isRequest(http.MethodGet, testMessagesPath)).Return(newTestResponse(http.StatusOK, messagesBody("1", "2")), nil).Once()
+		mDoer.EXPECT().Do(isRequest(http.MethodDelete, "/v1/messages/1")).Return(newTestResponse(http.StatusOK, nil), nil)
+		mDoer.EXPECT().Do(isRequest(http.MethodDelete, "/v1/messages/2")).
+			Return(newTestResponse(http.StatusBadRequest, readFile(t, "testdata/error_response.json")), nil)
+
+		c := newClient()
+		c.doer = mDoer
+		deleted, err := c.PurgeInbox(context.Background(), "user@example.com")
+		var httpErr *HTTPError
+		assert.ErrorAs(t, err, &httpErr)
+		assert.ErrorContains(t, err, "2: ")
+		assert.Equal(t, 1, deleted)
+	})
+
+	t.Run("error from ListEmailMessages", func(t *testing.T) {
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(isRequest(http.MethodGet, testMessagesPath)).Return(nil, assert.AnError)
+
+		c := newClient()
+		c.doer = mDoer
+		deleted, err := c.PurgeInbox(context.Background(), "user@example.com")
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Zero(t, deleted)
+	})
+
+	t.Run("never empty", func(t *testing.T) {
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(isRequest(http.MethodGet, testMessagesPath)).RunAndReturn(func(*http.Request) (*http.Response, error) {
+			return newTestResponse(http.StatusOK, messagesBody("1")), nil
+		}).Times(maxPurgeRounds)
+		mDoer.EXPECT().Do(isRequest(http.MethodDelete, "/v1/messages/1")).RunAndReturn(func(*http.Request) (*http.Response, error) {
+			return newTestResponse(http.StatusOK, nil), nil
+		}).Times(maxPurgeRounds)
+
+		c := newClient()
+		c.doer = mDoer
+		deleted, err := c.PurgeInbox(context.Background(), "user@example.com")
+		assert.ErrorIs(t, err, ErrInboxNotEmpty)
+		assert.Equal(t, maxPurgeRounds, deleted)
+	})
+}
@@ -0,0 +1,47 @@
+package tempmail
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// NewRequest creates a request to an API endpoint that the Client doesn't wrap yet.
+// It is the supported way to call new endpoints without waiting for a release.
+//
+// The path is relative to the API base URL, for example "/v1/emails". Dynamic segments
+// must be escaped by the caller, for example with url.PathEscape. A query string may be
+// appended to the path. If body is not nil, it is encoded as JSON.
+// The request carries the API key and the User-Agent header of the Client and the
+// headers set by With. Send it with Do.
+func (c *Client) NewRequest(ctx context.Context, method, path string, body interface{}) (*http.Request, error) {
+	if !strings.HasPrefix(path, "/") || strings.HasPrefix(path, "//") {
+		// Anything else could send the API key to another host.
+		return nil, &ValidationError{Field: "path", Value: path, Reason: "must be relative to the API base URL and start with a single /"}
+	}
+	return c.newRequest(ctx, OperationRequest, method, path, body)
+}
+
+// Do sends a request created by NewRequest the same way as the other methods of the Client:
+// with its retries, circuit breaker, timeouts, response limits, tracer and metrics.
+// A non-2xx response is returned as an *HTTPError, and the rate limit is parsed into Response.Rate.
+//
+// The response body is decoded as JSON into v. If v is an io.Writer, the body is copied
+// into it instead. If v is nil, the body is discarded. The body is always closed.
+func (c *Client) Do(req *http.Request, v interface{}) (*Response, error) {
+	if operationFromContext(req.Context()) == "" {
+		req = req.WithContext(context.WithValue(req.Context(), operationKey{}, OperationRequest))
+	}
+	return c.send(req, func(r *Response) error {
+		switch v := v.(type) {
+		case nil:
+			return nil
+		case io.Writer:
+			_, err := io.Copy(v, r.Body)
+			return err
+		default:
+			return c.decode(operationFromContext(req.Context()), r.Body, v)
+		}
+	})
+}
@@ -0,0 +1,126 @@
+package tempmail
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_NewRequest(t *testing.T) {
+	t.Run("request", func(t *testing.T) {
+		c := newClient().With(WithHeader("X-Trace-Id", "trace-1"))
+		req, err := c.NewRequest(context.Background(), http.MethodPost, "/v1/forwards?dry_run=true", map[string]string{"to": "user@example.com"})
+		require.NoError(t, err)
+
+		assert.Equal(t, http.MethodPost, req.Method)
+		assert.Equal(t, "https://api.temp-mail.io/v1/forwards?dry_run=true", req.URL.String())
+		assert.Equal(t, "API_KEY", req.Header.Get(headerAPIKey))
+		assert.Equal(t, userAgent, req.Header.Get("User-Agent"))
+		assert.Equal(t, "trace-1", req.Header.Get("X-Trace-Id"))
+		assert.Equal(t, OperationRequest, operationFromContext(req.Context()))
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"to":"user@example.com"}`, string(body))
+	})
+
+	t.Run("invalid path", func(t *testing.T) {
+		for _, path := range []string{"", "v1/domains", "//evil.example.com/v1/domains", "https://evil.example.com/v1/domains"} {
+			_, err := newClient().NewRequest(context.Background(), http.MethodGet, path, nil)
+			var validationErr *ValidationError
+			assert.ErrorAs(t, err, &validationErr, path)
+		}
+	})
+}
+
+func TestClient_Do(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("decode", func(t *testing.T) {
+		tracer := &testTracer{}
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(isRequest(http.MethodGet, "/v1/domains")).
+			Return(newRateResponse(http.StatusOK, readFile(t, "testdata/list_domains.json"), 100, 99, time.Unix(1700000000, 0)), nil).Once()
+
+		c := NewClient("API_KEY", nil, WithTracer(tracer))
+		c.doer = mDoer
+		req, err := c.NewRequest(ctx, http.MethodGet, "/v1/domains", nil)
+		require.NoError(t, err)
+
+		var domains ListDomainsResponse
+		resp, err := c.Do(req, &domains)
+		require.NoError(t, err)
+		assert.Len(t, domains.Domains, 3)
+		assert.Equal(t, 99, resp.Rate.Remaining)
+		assert.Equal(t, []Operation{OperationRequest}, tracer.ops)
+	})
+
+	t.Run("writer", func(t *testing.T) {
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(mock.Anything).Return(newTestResponse(http.StatusOK, []byte("raw bytes")), nil).Once()
+
+		c := newClient()
+		c.doer = mDoer
+		req, err := c.NewRequest(ctx, http.MethodGet, "/v1/attachments/1", nil)
+		require.NoError(t, err)
+
+		var b bytes.Buffer
+		_, err = c.Do(req, &b)
+		require.NoError(t, err)
+		assert.Equal(t, "raw bytes", b.String())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(mock.Anything).Return(newTestResponse(http.StatusNotFound, readFile(t, "testdata/error_response.json")), nil).Once()
+
+		c := newClient()
+		c.doer = mDoer
+		req, err := c.NewRequest(ctx, http.MethodDelete, "/v1/forwards/1", nil)
+		require.NoError(t, err)
+
+		_, err = c.Do(req, nil)
+		var httpErr *HTTPError
+		require.ErrorAs(t, err, &httpErr)
+		assert.Equal(t, "not_found", httpErr.ErrorDetails.Code)
+	})
+
+	t.Run("retries", func(t *testing.T) {
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(mock.MatchedBy(func(req *http.Request) bool {
+			body, err := io.ReadAll(req.Body)
+			return err == nil && string(body) == `{"name":"x"}`
+		})).RunAndReturn(statusResponse(http.StatusServiceUnavailable)).Once()
+		mDoer.EXPECT().Do(mock.Anything).RunAndReturn(statusResponse(http.StatusOK)).Once()
+
+		c := newClient().With(WithRetryPolicy(testRetryPolicy))
+		c.doer = mDoer
+		req, err := c.NewRequest(ctx, http.MethodPut, "/v1/forwards/1", map[string]string{"name": "x"})
+		require.NoError(t, err)
+
+		_, err = c.Do(req, nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("request not built by NewRequest", func(t *testing.T) {
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(mock.MatchedBy(func(req *http.Request) bool {
+			return operationFromContext(req.Context()) == OperationRequest
+		})).Return(newTestResponse(http.StatusOK, nil), nil).Once()
+
+		c := newClient()
+		c.doer = mDoer
+		req, err := http.NewRequest(http.MethodGet, baseURL+"/v1/domains", strings.NewReader(""))
+		require.NoError(t, err)
+
+		_, err = c.Do(req, nil)
+		require.NoError(t, err)
+	})
+}
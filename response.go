@@ -13,6 +13,9 @@ type Response struct {
 	// Explicitly specify the Rate type so Rate's String() receiver doesn't
 	// propagate to Response.
 	Rate Rate
+
+	// Cached reports whether the body was served from the Cache.
+	Cached bool
 }
 
 // newResponse creates a new Response for the provided http.Response.
@@ -0,0 +1,120 @@
+package tempmail
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// DefaultRetryPolicy is a RetryPolicy suitable for most callers.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 3,
+	MinBackoff: 200 * time.Millisecond,
+	MaxBackoff: 5 * time.Second,
+}
+
+// RetryPolicy describes how failed calls are retried. Transport errors, 429 Too Many Requests
+// and 5xx responses are retried. POST requests are never retried, since the API might have
+// processed them already.
+// The zero value doesn't retry.
+type RetryPolicy struct {
+	// MaxRetries is the maximum number of retries of a call.
+	MaxRetries int
+	// MinBackoff is the wait time before the first retry. It doubles with every retry.
+	MinBackoff time.Duration
+	// MaxBackoff is the maximum wait time before a retry. A 429 response whose rate limit
+	// resets later than MaxBackoff is not retried.
+	MaxBackoff time.Duration
+}
+
+// roundTripWithRetries sends the request and retries it according to the retry policy.
+// It returns the number of retries.
+func (c *Client) roundTripWithRetries(req *http.Request, handle func(r *Response) error) (*Response, int, error) {
+	policy := c.call.retry
+	for retries := 0; ; retries++ {
+		r, err := c.roundTrip(req, handle)
+		if policy == nil || retries >= policy.MaxRetries || !retryable(req, r, err) {
+			return r, retries, err
+		}
+		delay, ok := policy.backoff(retries, r, c.now())
+		if !ok {
+			return r, retries, err
+		}
+		next, cloneErr := cloneRequest(req)
+		if cloneErr != nil {
+			return r, retries, err
+		}
+		if sleepErr := sleep(req.Context(), delay); sleepErr != nil {
+			// Keep the context error, so a timeout during the backoff is reported as such.
+			return r, retries, errors.Join(sleepErr, err)
+		}
+		req = next
+	}
+}
+
+// retryable reports whether the outcome of the request may be retried.
+func retryable(req *http.Request, r *Response, err error) bool {
+	if req.Method == http.MethodPost {
+		return false
+	}
+	if err != nil && r == nil {
+		return !errors.Is(err, ErrCircuitOpen) && req.Context().Err() == nil
+	}
+	return r != nil && (r.StatusCode == http.StatusTooManyRequests || r.StatusCode >= 500)
+}
+
+// backoff returns the wait time before the retry with the given number of previous retries.
+// It reports false if the call shouldn't be retried because the rate limit resets too late.
+func (p RetryPolicy) backoff(retries int, r *Response, now time.Time) (time.Duration, bool) {
+	if r != nil && r.StatusCode == http.StatusTooManyRequests && r.Rate.Reset.After(now) {
+		wait := r.Rate.Reset.Sub(now)
+		return wait, p.MaxBackoff <= 0 || wait <= p.MaxBackoff
+	}
+
+	d := p.MinBackoff
+	for i := 0; i < retries && (p.MaxBackoff <= 0 || d < p.MaxBackoff); i++ {
+		d *= 2
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	if d <= 0 {
+		return 0, true
+	}
+	// Spread the retries of concurrent calls over [d/2, d).
+	//nolint:gosec // Jitter doesn't need a cryptographic source.
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1)), true
+}
+
+// cloneRequest returns a copy of the request with a fresh body, so it can be sent again.
+func cloneRequest(req *http.Request) (*http.Request, error) {
+	clone := req.Clone(req.Context())
+	if req.Body != nil && req.Body != http.NoBody {
+		if req.GetBody == nil {
+			return nil, http.ErrBodyNotAllowed
+		}
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		clone.Body = body
+	}
+	return clone, nil
+}
+
+// sleep waits for the duration or until the context is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
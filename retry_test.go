@@ -0,0 +1,139 @@
+package tempmail
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var testRetryPolicy = RetryPolicy{MaxRetries: 2, MinBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond}
+
+func TestClient_retries(t *testing.T) {
+	t.Run("server error", func(t *testing.T) {
+		metrics := &testMetrics{}
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(mock.Anything).RunAndReturn(statusResponse(http.StatusServiceUnavailable)).Once()
+		mDoer.EXPECT().Do(mock.Anything).RunAndReturn(statusResponse(http.StatusOK)).Once()
+
+		c := NewClient("API_KEY", nil, WithMetrics(metrics))
+		c.doer = mDoer
+		_, _, err := c.With(WithRetryPolicy(testRetryPolicy)).ListDomains(context.Background())
+		require.NoError(t, err)
+		require.Len(t, metrics.calls, 1)
+		assert.Equal(t, 1, metrics.calls[0].Retries)
+	})
+
+	t.Run("transport error", func(t *testing.T) {
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(mock.Anything).Return(nil, assert.AnError).Once()
+		mDoer.EXPECT().Do(mock.Anything).RunAndReturn(statusResponse(http.StatusOK)).Once()
+
+		c := newClient().With(WithRetryPolicy(testRetryPolicy))
+		c.doer = mDoer
+		_, err := c.DeleteMessage(context.Background(), "message")
+		require.NoError(t, err)
+	})
+
+	t.Run("retries exhausted", func(t *testing.T) {
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(mock.Anything).RunAndReturn(statusResponse(http.StatusInternalServerError)).Times(3)
+
+		c := newClient().With(WithRetryPolicy(testRetryPolicy))
+		c.doer = mDoer
+		_, _, err := c.ListDomains(context.Background())
+		var httpErr *HTTPError
+		require.ErrorAs(t, err, &httpErr)
+		assert.Equal(t, http.StatusInternalServerError, httpErr.Response.StatusCode)
+	})
+
+	t.Run("client errors are not retried", func(t *testing.T) {
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(mock.Anything).RunAndReturn(statusResponse(http.StatusBadRequest)).Once()
+
+		c := newClient().With(WithRetryPolicy(testRetryPolicy))
+		c.doer = mDoer
+		_, _, err := c.ListDomains(context.Background())
+		assert.Error(t, err)
+	})
+
+	t.Run("POST is not retried", func(t *testing.T) {
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(mock.Anything).RunAndReturn(statusResponse(http.StatusBadGateway)).Twice()
+
+		c := newClient().With(WithRetryPolicy(testRetryPolicy))
+		c.doer = mDoer
+		_, _, err := c.CreateEmail(context.Background(), CreateEmailOptions{})
+		assert.Error(t, err)
+
+		// Not even with an idempotency key, since the API might not deduplicate requests.
+		_, _, err = c.With(WithIdempotencyKey("create-1")).CreateEmail(context.Background(), CreateEmailOptions{})
+		assert.Error(t, err)
+	})
+
+	t.Run("rate limit resets too late", func(t *testing.T) {
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(mock.Anything).
+			Return(newRateResponse(http.StatusTooManyRequests, readFile(t, "testdata/error_response.json"), 10, 0, time.Now().Add(time.Hour)), nil).Once()
+
+		c := newClient().With(WithRetryPolicy(testRetryPolicy))
+		c.doer = mDoer
+		_, _, err := c.ListDomains(context.Background())
+		assert.Error(t, err)
+	})
+
+	t.Run("timeout during backoff", func(t *testing.T) {
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(mock.Anything).RunAndReturn(statusResponse(http.StatusInternalServerError)).Once()
+
+		policy := RetryPolicy{MaxRetries: 1, MinBackoff: time.Hour, MaxBackoff: time.Hour}
+		c := newClient().With(WithRetryPolicy(policy), WithTimeout(10*time.Millisecond))
+		c.doer = mDoer
+		_, _, err := c.ListDomains(context.Background())
+		assert.ErrorIs(t, err, ErrTimeout)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		var httpErr *HTTPError
+		require.ErrorAs(t, err, &httpErr)
+		assert.Equal(t, http.StatusInternalServerError, httpErr.Response.StatusCode)
+	})
+
+	t.Run("no policy", func(t *testing.T) {
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(mock.Anything).RunAndReturn(statusResponse(http.StatusInternalServerError)).Once()
+
+		c := newClient()
+		c.doer = mDoer
+		_, _, err := c.ListDomains(context.Background())
+		assert.Error(t, err)
+	})
+}
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	p := RetryPolicy{MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	for retries, max := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second} {
+		d, ok := p.backoff(retries, nil, now)
+		assert.True(t, ok)
+		assert.GreaterOrEqual(t, d, max/2, "retries %d", retries)
+		assert.LessOrEqual(t, d, max, "retries %d", retries)
+	}
+
+	rateLimited := &Response{Response: &http.Response{StatusCode: http.StatusTooManyRequests}}
+	rateLimited.Rate.Reset = now.Add(500 * time.Millisecond)
+	d, ok := p.backoff(0, rateLimited, now)
+	assert.True(t, ok)
+	assert.Equal(t, 500*time.Millisecond, d)
+
+	rateLimited.Rate.Reset = now.Add(time.Minute)
+	_, ok = p.backoff(0, rateLimited, now)
+	assert.False(t, ok)
+
+	d, ok = RetryPolicy{}.backoff(3, nil, now)
+	assert.True(t, ok)
+	assert.Zero(t, d)
+}
@@ -0,0 +1,311 @@
+package tempmail
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrNotStored is returned by Store.Load when the email address is not in the store.
+var ErrNotStored = errors.New("tempmail: email address not stored")
+
+// StoredEmail represents an email address persisted in a Store.
+type StoredEmail struct {
+	// Email is the email address.
+	Email string `json:"email"`
+	// ExpiresAt is the time at which the email address expires.
+	ExpiresAt time.Time `json:"expires_at"`
+	// Labels are arbitrary user-defined labels, for example the job that created the address.
+	Labels map[string]string `json:"labels,omitempty"`
+	// SeenMessageIDs are the IDs of the messages that have already been processed.
+	SeenMessageIDs []string `json:"seen_message_ids,omitempty"`
+}
+
+// Seen reports whether the message has already been processed.
+func (e StoredEmail) Seen(messageID string) bool {
+	for _, id := range e.SeenMessageIDs {
+		if id == messageID {
+			return true
+		}
+	}
+	return false
+}
+
+// MarkSeen marks the messages as processed.
+func (e *StoredEmail) MarkSeen(messageIDs ...string) {
+	for _, id := range messageIDs {
+		if !e.Seen(id) {
+			e.SeenMessageIDs = append(e.SeenMessageIDs, id)
+		}
+	}
+}
+
+// Store persists email addresses, so they can be used across process restarts.
+// Implementations must be safe for concurrent use.
+type Store interface {
+	// Save creates or replaces the stored email address.
+	Save(ctx context.Context, email StoredEmail) error
+	// Load returns the stored email address or ErrNotStored.
+	Load(ctx context.Context, email string) (StoredEmail, error)
+	// List returns all stored email addresses ordered by address.
+	List(ctx context.Context) ([]StoredEmail, error)
+	// Delete removes the email address from the store. Deleting a missing address is not an error.
+	Delete(ctx context.Context, email string) error
+}
+
+// WithStore makes the Client save every email address it creates to the store
+// and remove every email address it deletes.
+func WithStore(s Store) ClientOption {
+	return func(c *Client) {
+		c.store = s
+	}
+}
+
+// ListUnseenMessages returns the messages of the stored email address that haven't been
+// returned by ListUnseenMessages before, and marks them as seen in the store.
+// It requires a Store configured with WithStore.
+func (c *Client) ListUnseenMessages(ctx context.Context, email string) ([]Message, *Response, error) {
+	if c.store == nil {
+		return nil, nil, errors.New("tempmail: ListUnseenMessages requires a Store")
+	}
+	stored, err := c.store.Load(ctx, email)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !c.now().Before(stored.ExpiresAt) {
+		return nil, nil, fmt.Errorf("%w: %s", ErrEmailExpired, email)
+	}
+
+	resp, r, err := c.ListEmailMessages(ctx, email)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var unseen []Message
+	for _, m := range resp.Messages {
+		if !stored.Seen(m.ID) {
+			unseen = append(unseen, m)
+			stored.MarkSeen(m.ID)
+		}
+	}
+	if len(unseen) > 0 {
+		if err := c.store.Save(ctx, stored); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return unseen, r, nil
+}
+
+// storeCreated saves the created email address with its labels to the configured Store.
+func (c *Client) storeCreated(ctx context.Context, result CreateEmailResponse, labels map[string]string) error {
+	if c.store == nil {
+		return nil
+	}
+	err := c.store.Save(ctx, StoredEmail{Email: result.Email, ExpiresAt: result.ExpiresAt, Labels: labels})
+	if err != nil {
+		return fmt.Errorf("tempmail: save created email: %w", err)
+	}
+	return nil
+}
+
+// storeDeleted removes the deleted email address from the configured Store.
+func (c *Client) storeDeleted(ctx context.Context, email string) error {
+	if c.store == nil {
+		return nil
+	}
+	if err := c.store.Delete(ctx, email); err != nil {
+		return fmt.Errorf("tempmail: delete stored email: %w", err)
+	}
+	return nil
+}
+
+// MemoryStore is a Store that keeps email addresses in memory.
+type MemoryStore struct {
+	mu     sync.Mutex
+	emails map[string]StoredEmail
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{emails: make(map[string]StoredEmail)}
+}
+
+// Save implements Store.
+func (s *MemoryStore) Save(_ context.Context, email StoredEmail) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.emails[email.Email] = copyStoredEmail(email)
+	return nil
+}
+
+// Load implements Store.
+func (s *MemoryStore) Load(_ context.Context, email string) (StoredEmail, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.emails[email]
+	if !ok {
+		return StoredEmail{}, ErrNotStored
+	}
+	return copyStoredEmail(e), nil
+}
+
+// List implements Store.
+func (s *MemoryStore) List(_ context.Context) ([]StoredEmail, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return sortedStoredEmails(s.emails), nil
+}
+
+// Delete implements Store.
+func (s *MemoryStore) Delete(_ context.Context, email string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.emails, email)
+	return nil
+}
+
+// FileStore is a Store that keeps email addresses in a JSON file.
+// Every change rewrites the file atomically, so it can be read by another process at any time.
+type FileStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileStore creates a FileStore backed by the file at path.
+// The file is created on the first Save.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// Save implements Store.
+func (s *FileStore) Save(_ context.Context, email StoredEmail) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	emails, err := s.read()
+	if err != nil {
+		return err
+	}
+	emails[email.Email] = email
+	return s.write(emails)
+}
+
+// Load implements Store.
+func (s *FileStore) Load(_ context.Context, email string) (StoredEmail, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	emails, err := s.read()
+	if err != nil {
+		return StoredEmail{}, err
+	}
+	e, ok := emails[email]
+	if !ok {
+		return StoredEmail{}, ErrNotStored
+	}
+	return e, nil
+}
+
+// List implements Store.
+func (s *FileStore) List(_ context.Context) ([]StoredEmail, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	emails, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	return sortedStoredEmails(emails), nil
+}
+
+// Delete implements Store.
+func (s *FileStore) Delete(_ context.Context, email string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	emails, err := s.read()
+	if err != nil {
+		return err
+	}
+	if _, ok := emails[email]; !ok {
+		return nil
+	}
+	delete(emails, email)
+	return s.write(emails)
+}
+
+// read reads all email addresses from the file. A missing file is an empty store.
+func (s *FileStore) read() (map[string]StoredEmail, error) {
+	emails := make(map[string]StoredEmail)
+	b, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return emails, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var list []StoredEmail
+	if err := json.Unmarshal(b, &list); err != nil {
+		return nil, fmt.Errorf("tempmail: decode store %s: %w", s.path, err)
+	}
+	for _, e := range list {
+		emails[e.Email] = e
+	}
+	return emails, nil
+}
+
+// write replaces the file with the given email addresses.
+func (s *FileStore) write(emails map[string]StoredEmail) error {
+	b, err := json.MarshalIndent(sortedStoredEmails(emails), "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// sortedStoredEmails returns the email addresses ordered by address.
+func sortedStoredEmails(emails map[string]StoredEmail) []StoredEmail {
+	result := make([]StoredEmail, 0, len(emails))
+	for _, e := range emails {
+		result = append(result, copyStoredEmail(e))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Email < result[j].Email
+	})
+	return result
+}
+
+// copyStoredEmail returns a deep copy, so callers can't modify stored values.
+func copyStoredEmail(e StoredEmail) StoredEmail {
+	if e.Labels != nil {
+		labels := make(map[string]string, len(e.Labels))
+		for k, v := range e.Labels {
+			labels[k] = v
+		}
+		e.Labels = labels
+	}
+	if e.SeenMessageIDs != nil {
+		e.SeenMessageIDs = append([]string(nil), e.SeenMessageIDs...)
+	}
+	return e
+}
+
+var (
+	_ Store = (*MemoryStore)(nil)
+	_ Store = (*FileStore)(nil)
+)
@@ -0,0 +1,176 @@
+package tempmail
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStores(t *testing.T) {
+	stores := map[string]func(t *testing.T) Store{
+		"memory": func(*testing.T) Store { return NewMemoryStore() },
+		"file": func(t *testing.T) Store {
+			return NewFileStore(filepath.Join(t.TempDir(), "emails.json"))
+		},
+	}
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			s := newStore(t)
+
+			_, err := s.Load(ctx, "a@example.com")
+			assert.ErrorIs(t, err, ErrNotStored)
+			list, err := s.List(ctx)
+			require.NoError(t, err)
+			assert.Empty(t, list)
+
+			expiresAt := time.Date(2025, 1, 31, 22, 0, 0, 0, time.UTC)
+			b := StoredEmail{Email: "b@example.com", ExpiresAt: expiresAt, Labels: map[string]string{"job": "nightly"}}
+			a := StoredEmail{Email: "a@example.com", ExpiresAt: expiresAt, SeenMessageIDs: []string{"1"}}
+			require.NoError(t, s.Save(ctx, b))
+			require.NoError(t, s.Save(ctx, a))
+
+			loaded, err := s.Load(ctx, "b@example.com")
+			require.NoError(t, err)
+			assert.Equal(t, b, loaded)
+
+			// Stored values can't be changed through returned values.
+			loaded.Labels["job"] = "changed"
+			loaded, err = s.Load(ctx, "b@example.com")
+			require.NoError(t, err)
+			assert.Equal(t, "nightly", loaded.Labels["job"])
+
+			list, err = s.List(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, []StoredEmail{a, b}, list)
+
+			require.NoError(t, s.Delete(ctx, "a@example.com"))
+			require.NoError(t, s.Delete(ctx, "a@example.com"))
+			list, err = s.List(ctx)
+			require.NoError(t, err)
+			assert.Equal(t, []StoredEmail{b}, list)
+		})
+	}
+}
+
+func TestFileStore(t *testing.T) {
+	t.Run("persists across instances", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "emails.json")
+		e := StoredEmail{Email: "a@example.com", ExpiresAt: time.Date(2025, 1, 31, 22, 0, 0, 0, time.UTC)}
+		require.NoError(t, NewFileStore(path).Save(context.Background(), e))
+
+		loaded, err := NewFileStore(path).Load(context.Background(), "a@example.com")
+		require.NoError(t, err)
+		assert.Equal(t, e, loaded)
+	})
+
+	t.Run("invalid file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "emails.json")
+		require.NoError(t, os.WriteFile(path, []byte("invalid json"), 0o600))
+
+		_, err := NewFileStore(path).List(context.Background())
+		assert.ErrorContains(t, err, "tempmail: decode store")
+	})
+}
+
+func TestStoredEmail_MarkSeen(t *testing.T) {
+	var e StoredEmail
+	assert.False(t, e.Seen("1"))
+	e.MarkSeen("1", "2", "1")
+	assert.Equal(t, []string{"1", "2"}, e.SeenMessageIDs)
+	assert.True(t, e.Seen("2"))
+}
+
+func TestWithStore(t *testing.T) {
+	mDoer := newMockDoer(t)
+	mDoer.EXPECT().Do(isRequest(http.MethodPost, "/v1/emails")).
+		Return(newTestResponse(http.StatusOK, readFile(t, "testdata/create_email.json")), nil)
+	mDoer.EXPECT().Do(isRequest(http.MethodDelete, "/v1/emails/test@example.com")).
+		Return(newTestResponse(http.StatusOK, []byte("{}")), nil)
+
+	store := NewMemoryStore()
+	c := NewClient("API_KEY", nil, WithStore(store))
+	c.doer = mDoer
+
+	labels := map[string]string{"job": "signup"}
+	result, _, err := c.CreateEmail(context.Background(), CreateEmailOptions{Labels: labels})
+	require.NoError(t, err)
+	stored, err := store.Load(context.Background(), "test@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, StoredEmail{Email: "test@example.com", ExpiresAt: result.ExpiresAt, Labels: labels}, stored)
+
+	_, err = c.DeleteEmail(context.Background(), "test@example.com")
+	require.NoError(t, err)
+	_, err = store.Load(context.Background(), "test@example.com")
+	assert.ErrorIs(t, err, ErrNotStored)
+}
+
+func TestWithStore_expired(t *testing.T) {
+	store := NewFileStore(filepath.Join(t.TempDir(), "emails.json"))
+	c, now := newExpiryTestClient(t, newMockDoer(t), WithStore(store))
+	*now = now.Add(2 * time.Hour)
+
+	_, err := c.DeleteEmail(context.Background(), "test@example.com")
+	assert.ErrorIs(t, err, ErrEmailExpired)
+	_, err = store.Load(context.Background(), "test@example.com")
+	assert.ErrorIs(t, err, ErrNotStored)
+}
+
+func TestClient_ListUnseenMessages(t *testing.T) {
+	t.Run("resumes from store", func(t *testing.T) {
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(isRequest(http.MethodGet, "/v1/emails/user@example.com/messages")).
+			RunAndReturn(func(*http.Request) (*http.Response, error) {
+				return newTestResponse(http.StatusOK, readFile(t, "testdata/list_email_messages.json")), nil
+			})
+
+		store := NewFileStore(filepath.Join(t.TempDir(), "emails.json"))
+		require.NoError(t, store.Save(context.Background(), StoredEmail{
+			Email:     "user@example.com",
+			ExpiresAt: time.Now().Add(time.Hour),
+		}))
+
+		c := NewClient("API_KEY", nil, WithStore(store))
+		c.doer = mDoer
+		messages, _, err := c.ListUnseenMessages(context.Background(), "user@example.com")
+		require.NoError(t, err)
+		require.Len(t, messages, 1)
+		assert.Equal(t, "01JE97FT950QRPDYGDXJ4R43QR", messages[0].ID)
+
+		// A new process with the same store doesn't see the message again.
+		c = NewClient("API_KEY", nil, WithStore(store))
+		c.doer = mDoer
+		messages, _, err = c.ListUnseenMessages(context.Background(), "user@example.com")
+		require.NoError(t, err)
+		assert.Empty(t, messages)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		store := NewMemoryStore()
+		require.NoError(t, store.Save(context.Background(), StoredEmail{
+			Email:     "user@example.com",
+			ExpiresAt: time.Now().Add(-time.Second),
+		}))
+
+		c := NewClient("API_KEY", nil, WithStore(store))
+		_, _, err := c.ListUnseenMessages(context.Background(), "user@example.com")
+		assert.ErrorIs(t, err, ErrEmailExpired)
+	})
+
+	t.Run("not stored", func(t *testing.T) {
+		c := NewClient("API_KEY", nil, WithStore(NewMemoryStore()))
+		_, _, err := c.ListUnseenMessages(context.Background(), "user@example.com")
+		assert.ErrorIs(t, err, ErrNotStored)
+	})
+
+	t.Run("without store", func(t *testing.T) {
+		_, _, err := newClient().ListUnseenMessages(context.Background(), "user@example.com")
+		assert.EqualError(t, err, "tempmail: ListUnseenMessages requires a Store")
+	})
+}
@@ -0,0 +1,104 @@
+package tempmail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrTimeout is matched by the *TimeoutError returned when a call exceeds its timeout.
+var ErrTimeout = errors.New("tempmail: call timed out")
+
+// DefaultTimeouts are the Timeouts used by a Client created without WithDefaultTimeouts.
+var DefaultTimeouts = Timeouts{
+	Metadata:   10 * time.Second,
+	Message:    30 * time.Second,
+	Attachment: 2 * time.Minute,
+	Mutation:   30 * time.Second,
+}
+
+// Timeouts are the default timeouts of the operation classes. They only apply when
+// the context of a call has no deadline and no WithTimeout call option is set.
+// A zero field disables the default timeout of its class.
+type Timeouts struct {
+	// Metadata is the timeout of ListDomains and RateLimit.
+	Metadata time.Duration
+	// Message is the timeout of ListEmailMessages, GetMessage, GetMessageSourceCode and Do.
+	Message time.Duration
+	// Attachment is the timeout of DownloadAttachment.
+	Attachment time.Duration
+	// Mutation is the timeout of CreateEmail, DeleteEmail and DeleteMessage.
+	Mutation time.Duration
+}
+
+// WithDefaultTimeouts sets the default timeouts of the operation classes.
+// Use a zero Timeouts to disable them.
+func WithDefaultTimeouts(t Timeouts) ClientOption {
+	return func(c *Client) {
+		c.timeouts = t
+	}
+}
+
+// timeout returns the default timeout of the operation.
+func (t Timeouts) timeout(op Operation) time.Duration {
+	switch op.class() {
+	case classMetadata:
+		return t.Metadata
+	case classMessage:
+		return t.Message
+	case classAttachment:
+		return t.Attachment
+	case classMutation:
+		return t.Mutation
+	default:
+		return 0
+	}
+}
+
+// TimeoutError is returned when a call exceeds the timeout set by WithTimeout or
+// the default timeout of its operation. It is not returned when the deadline of
+// the caller's context is exceeded.
+type TimeoutError struct {
+	// Operation is the Client method that timed out.
+	Operation Operation
+	// Timeout is the exceeded timeout.
+	Timeout time.Duration
+	// Err is the underlying error, which matches context.DeadlineExceeded.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("tempmail: %s timed out after %s: %v", e.Operation, e.Timeout, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the target is ErrTimeout.
+func (e *TimeoutError) Is(target error) bool {
+	return target == ErrTimeout
+}
+
+// callTimeout returns the timeout of a call of the operation with the context.
+func (c *Client) callTimeout(ctx context.Context, op Operation) time.Duration {
+	if c.call.timeout > 0 {
+		return c.call.timeout
+	}
+	if _, ok := ctx.Deadline(); ok {
+		return 0
+	}
+	return c.timeouts.timeout(op)
+}
+
+// timeoutError wraps err in a *TimeoutError if the timeout of the call, rather than
+// the caller's context, ended it.
+func timeoutError(parent, ctx context.Context, op Operation, timeout time.Duration, err error) error {
+	if err == nil || parent.Err() != nil || !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return err
+	}
+	return &TimeoutError{Operation: op, Timeout: timeout, Err: err}
+}
@@ -0,0 +1,105 @@
+package tempmail
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingDoer returns a doer that blocks until the request context is done.
+func blockingDoer(t *testing.T) *mockDoer {
+	mDoer := newMockDoer(t)
+	mDoer.EXPECT().Do(mock.Anything).RunAndReturn(func(req *http.Request) (*http.Response, error) {
+		<-req.Context().Done()
+		return nil, req.Context().Err()
+	})
+	return mDoer
+}
+
+func TestClient_defaultTimeouts(t *testing.T) {
+	t.Run("default timeout", func(t *testing.T) {
+		c := NewClient("API_KEY", nil, WithDefaultTimeouts(Timeouts{Metadata: 10 * time.Millisecond}))
+		c.doer = blockingDoer(t)
+
+		_, _, err := c.ListDomains(context.Background())
+		require.ErrorIs(t, err, ErrTimeout)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		var timeoutErr *TimeoutError
+		require.ErrorAs(t, err, &timeoutErr)
+		assert.Equal(t, OperationListDomains, timeoutErr.Operation)
+		assert.Equal(t, 10*time.Millisecond, timeoutErr.Timeout)
+		assert.Contains(t, err.Error(), "ListDomains timed out after 10ms")
+	})
+
+	t.Run("call option", func(t *testing.T) {
+		c := NewClient("API_KEY", nil, WithDefaultTimeouts(Timeouts{}))
+		c.doer = blockingDoer(t)
+
+		_, _, err := c.With(WithTimeout(10*time.Millisecond)).DownloadAttachment(context.Background(), "attachment")
+		var timeoutErr *TimeoutError
+		require.ErrorAs(t, err, &timeoutErr)
+		assert.Equal(t, OperationDownloadAttachment, timeoutErr.Operation)
+	})
+
+	t.Run("context deadline", func(t *testing.T) {
+		c := NewClient("API_KEY", nil, WithDefaultTimeouts(Timeouts{Metadata: time.Hour}))
+		c.doer = blockingDoer(t)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		_, _, err := c.ListDomains(ctx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.NotErrorIs(t, err, ErrTimeout)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(mock.MatchedBy(func(req *http.Request) bool {
+			_, ok := req.Context().Deadline()
+			return !ok
+		})).Return(newTestResponse(http.StatusOK, readFile(t, "testdata/list_domains.json")), nil).Once()
+
+		c := NewClient("API_KEY", nil, WithDefaultTimeouts(Timeouts{}))
+		c.doer = mDoer
+		_, _, err := c.ListDomains(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("default", func(t *testing.T) {
+		mDoer := newMockDoer(t)
+		mDoer.EXPECT().Do(mock.MatchedBy(func(req *http.Request) bool {
+			deadline, ok := req.Context().Deadline()
+			return ok && time.Until(deadline) <= DefaultTimeouts.Mutation
+		})).Return(newTestResponse(http.StatusOK, nil), nil).Once()
+
+		c := newClient()
+		c.doer = mDoer
+		_, err := c.DeleteMessage(context.Background(), "message")
+		require.NoError(t, err)
+	})
+}
+
+func TestTimeouts_timeout(t *testing.T) {
+	timeouts := Timeouts{Metadata: 1, Message: 2, Attachment: 3, Mutation: 4}
+	tests := map[Operation]time.Duration{
+		OperationListDomains:          1,
+		OperationRateLimit:            1,
+		OperationListEmailMessages:    2,
+		OperationGetMessage:           2,
+		OperationGetMessageSourceCode: 2,
+		OperationDownloadAttachment:   3,
+		OperationCreateEmail:          4,
+		OperationDeleteEmail:          4,
+		OperationDeleteMessage:        4,
+		OperationRequest:              2,
+		Operation("Unknown"):          0,
+	}
+	for op, want := range tests {
+		assert.Equal(t, want, timeouts.timeout(op), op)
+	}
+}